@@ -0,0 +1,113 @@
+package gorocksdb
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackgroundError returns the background error that put the database in
+// read-only mode, such as an IO error while flushing, or nil if writes are
+// accepted.
+//
+// The RocksDB C API gives no access to the background error, so it is
+// retrieved by writing an empty batch, which fails with the background error
+// while the database is read-only. RocksDB resumes by itself after some
+// errors, such as running out of space with an SstFileManager, but DB.Resume
+// is not exposed by the C API: the database must be reopened to recover from
+// the other ones.
+func (db *DB) BackgroundError() error {
+	n, err := db.backgroundErrors()
+	if err != nil || n == 0 {
+		return err
+	}
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	wo.DisableWAL(true)
+	wo.SetNoSlowdown(true)
+	wb := NewWriteBatch()
+	defer wb.Destroy()
+	if err := db.Write(wo, wb); err != nil && !strings.Contains(err.Error(), "Write stall") {
+		return err
+	}
+	return nil
+}
+
+// BackgroundErrorEvent describes background errors reported to the handler
+// of WatchBackgroundErrors.
+type BackgroundErrorEvent struct {
+	// Count is the number of background errors since the database was
+	// opened.
+	Count uint64
+	// New is the number of background errors since the last event.
+	New uint64
+	// Err is the background error that put the database in read-only
+	// mode, nil if the errors did not stop the writes.
+	Err error
+}
+
+func (e BackgroundErrorEvent) String() string {
+	return fmt.Sprintf("%d new background errors (%d in total): %v", e.New, e.Count, e.Err)
+}
+
+// WatchBackgroundErrors calls handler whenever new background errors occur,
+// checking the database every interval until stop is called. It only
+// reports the errors: the C API doesn't expose DB.Resume, so the database
+// can't be recovered in place, and recovering from an error RocksDB doesn't
+// resume from by itself means closing and reopening it.
+//
+// The handler is called from a separate goroutine, and may call stop. stop
+// waits for the database to stop being checked, but not for a running
+// handler, so it must be called before the database is closed, and the
+// handler must not use the database once it is.
+func (db *DB) WatchBackgroundErrors(interval time.Duration, handler func(BackgroundErrorEvent)) (stop func()) {
+	return watchBackgroundErrors(interval, db.backgroundErrors, db.BackgroundError, handler)
+}
+
+// watchBackgroundErrors polls the number of background errors with count,
+// and calls handler with the error returned by probe when it increases.
+func watchBackgroundErrors(interval time.Duration, count func() (uint64, error), probe func() error, handler func(BackgroundErrorEvent)) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	events := make(chan BackgroundErrorEvent)
+	go func() {
+		for e := range events {
+			handler(e)
+		}
+	}()
+	go func() {
+		defer close(stopped)
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last, _ := count()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			n, err := count()
+			if err != nil || n <= last {
+				continue
+			}
+			select {
+			case <-done:
+				return
+			case events <- BackgroundErrorEvent{Count: n, New: n - last, Err: probe()}:
+			}
+			last = n
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
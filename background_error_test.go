@@ -0,0 +1,70 @@
+package gorocksdb
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDBBackgroundError(t *testing.T) {
+	db := newTestDB(t, "TestDBBackgroundError", nil)
+	defer db.Close()
+
+	events := make(chan BackgroundErrorEvent, 1)
+	stop := db.WatchBackgroundErrors(time.Millisecond, func(e BackgroundErrorEvent) { events <- e })
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.Nil(t, db.BackgroundError())
+
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	stop()
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected background errors: %v", e)
+	default:
+	}
+
+	// the probe doesn't write anything
+	v, err := db.GetBytes(NewDefaultReadOptions(), []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("val"))
+}
+
+func TestWatchBackgroundErrors(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		count uint64
+	)
+	errIO := errors.New("IO error: No space left on device")
+	events := make(chan BackgroundErrorEvent, 1)
+	var stop func()
+	stop = watchBackgroundErrors(time.Millisecond, func() (uint64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return count, nil
+	}, func() error {
+		return errIO
+	}, func(e BackgroundErrorEvent) {
+		// stopping from the handler doesn't wait for it
+		stop()
+		events <- e
+	})
+
+	mu.Lock()
+	count = 2
+	mu.Unlock()
+
+	select {
+	case e := <-events:
+		ensure.DeepEqual(t, e, BackgroundErrorEvent{Count: 2, New: 2, Err: errIO})
+	case <-time.After(time.Second):
+		t.Fatal("no background error event")
+	}
+	stop()
+}
@@ -15,7 +15,9 @@ func NewLRUCache(capacity int) *Cache {
 
 // NewNativeCache creates a Cache object.
 func NewNativeCache(c *C.rocksdb_cache_t) *Cache {
-	return &Cache{c}
+	cache := &Cache{c}
+	trackAlloc("Cache", cache)
+	return cache
 }
 
 // GetUsage returns the Cache memory usage.
@@ -30,6 +32,7 @@ func (c *Cache) GetPinnedUsage() int {
 
 // Destroy deallocates the Cache object.
 func (c *Cache) Destroy() {
+	trackFree(c)
 	C.rocksdb_cache_destroy(c.c)
 	c.c = nil
 }
@@ -12,7 +12,9 @@ type ColumnFamilyHandle struct {
 
 // NewNativeColumnFamilyHandle creates a ColumnFamilyHandle object.
 func NewNativeColumnFamilyHandle(c *C.rocksdb_column_family_handle_t) *ColumnFamilyHandle {
-	return &ColumnFamilyHandle{c}
+	h := &ColumnFamilyHandle{c}
+	trackAlloc("ColumnFamilyHandle", h)
+	return h
 }
 
 // UnsafeGetCFHandler returns the underlying c column family handle.
@@ -22,6 +24,7 @@ func (h *ColumnFamilyHandle) UnsafeGetCFHandler() unsafe.Pointer {
 
 // Destroy calls the destructor of the underlying column family handle.
 func (h *ColumnFamilyHandle) Destroy() {
+	trackFree(h)
 	C.rocksdb_column_family_handle_destroy(h.c)
 }
 
@@ -0,0 +1,10 @@
+//go:build !v7
+// +build !v7
+
+package gorocksdb
+
+// name returns the name of the column family, which the C API can only tell
+// since RocksDB 7, "" otherwise.
+func (h *ColumnFamilyHandle) name() string {
+	return ""
+}
@@ -0,0 +1,26 @@
+//go:build v7
+// +build v7
+
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import "unsafe"
+
+// Name returns the name of the column family.
+func (h *ColumnFamilyHandle) Name() string {
+	var cLen C.size_t
+	cName := C.rocksdb_column_family_handle_get_name(h.c, &cLen)
+	defer C.free(unsafe.Pointer(cName))
+	return C.GoStringN(cName, C.int(cLen))
+}
+
+// ID returns the ID of the column family, unique within the database.
+func (h *ColumnFamilyHandle) ID() uint32 {
+	return uint32(C.rocksdb_column_family_handle_get_id(h.c))
+}
+
+func (h *ColumnFamilyHandle) name() string {
+	return h.Name()
+}
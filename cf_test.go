@@ -227,3 +227,76 @@ func TestColumnFamilyMultiGet(t *testing.T) {
 	ensure.DeepEqual(t, values[1].Data(), givenVal2)
 	ensure.DeepEqual(t, values[2].Data(), givenVal3)
 }
+
+func TestColumnFamilyMultiGetPinned(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestColumnFamilyMultiGetPinned")
+	defer cleanup()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+	)
+
+	// create
+	ensure.Nil(t, db.PutCF(wo, cfh[0], givenKey1, givenVal1))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey2, givenVal2))
+
+	// column family 1 only has givenKey2
+	values, err := db.MultiGetPinnedCF(ro, cfh[1], givenKey1, givenKey2)
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(values), 2)
+
+	ensure.DeepEqual(t, values[0].Data(), []byte(nil))
+	ensure.DeepEqual(t, values[1].Data(), givenVal2)
+}
+
+func TestColumnFamilyMultiGetBatch(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestColumnFamilyMultiGetBatch")
+	defer cleanup()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenKey3 = []byte("hello3")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		givenVal3 = []byte("world3")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+	)
+
+	// create
+	ensure.Nil(t, db.PutCF(wo, cfh[0], givenKey1, givenVal1))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey2, givenVal2))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey3, givenVal3))
+
+	reqs := []KeyRequest{
+		{CF: cfh[1], Key: givenKey3},
+		{CF: nil, Key: givenKey1},
+		{CF: cfh[1], Key: givenKey1},
+		{CF: cfh[1], Key: givenKey2},
+	}
+	res := db.MultiGetBatch(ro, reqs)
+	ensure.DeepEqual(t, res.Results, []KeyResult{
+		{Value: givenVal3, Found: true},
+		{Value: givenVal1, Found: true},
+		{},
+		{Value: givenVal2, Found: true},
+	})
+	res.Release()
+
+	// the keys are read in order, so hello2 crosses the limit and hello3
+	// isn't read.
+	ro.SetValueSizeSoftLimit(1)
+	res = db.MultiGetBatch(ro, []KeyRequest{reqs[0], reqs[3]})
+	defer res.Release()
+	ensure.DeepEqual(t, res.Results, []KeyResult{
+		{Err: ErrValueSizeSoftLimitExceeded},
+		{Value: givenVal2, Found: true},
+	})
+}
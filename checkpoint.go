@@ -17,7 +17,9 @@ type Checkpoint struct {
 
 // NewNativeCheckpoint creates a new checkpoint.
 func NewNativeCheckpoint(c *C.rocksdb_checkpoint_t) *Checkpoint {
-	return &Checkpoint{c}
+	checkpoint := &Checkpoint{c}
+	trackAlloc("Checkpoint", checkpoint)
+	return checkpoint
 }
 
 // CreateCheckpoint builds an openable snapshot of RocksDB on the same disk, which
@@ -51,6 +53,7 @@ func (checkpoint *Checkpoint) CreateCheckpoint(checkpoint_dir string, log_size_f
 
 // Destroy deallocates the Checkpoint object.
 func (checkpoint *Checkpoint) Destroy() {
+	trackFree(checkpoint)
 	C.rocksdb_checkpoint_object_destroy(checkpoint.c)
 	checkpoint.c = nil
 }
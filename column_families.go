@@ -0,0 +1,216 @@
+package gorocksdb
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ColumnFamilies tracks the column family handles of a database by name.
+// The handles it holds are owned by the registry: they stay valid until they
+// are dropped or the database is closed, which destroys them.
+//
+// The registry of a database opened by OpenDbWithColumnFamilies or
+// OpenTransactionDbWithColumnFamilies holds all its column families, the one
+// of a database opened otherwise only the ones created through it.
+type ColumnFamilies struct {
+	mu      sync.RWMutex
+	handles map[string]*ColumnFamilyHandle
+	create  func(opts *Options, name string) (*ColumnFamilyHandle, error)
+	drop    func(cf *ColumnFamilyHandle) error
+}
+
+// init binds the registry to its database, on first use.
+func (c *ColumnFamilies) init(create func(*Options, string) (*ColumnFamilyHandle, error), drop func(*ColumnFamilyHandle) error) *ColumnFamilies {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.create == nil {
+		c.create, c.drop = create, drop
+	}
+	return c
+}
+
+// CF returns the handle of the column family, nil if it's not tracked.
+func (c *ColumnFamilies) CF(name string) *ColumnFamilyHandle {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.handles[name]
+}
+
+// Names returns the names of the tracked column families, "default" first and
+// the others in name order.
+func (c *ColumnFamilies) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.handles))
+	for name := range c.handles {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "default" || names[j] == "default" {
+			return names[i] == "default"
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// Create creates a column family with the options and tracks its handle.
+func (c *ColumnFamilies) Create(opts *Options, name string) (*ColumnFamilyHandle, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.handles[name]; ok {
+		return nil, errors.New("Invalid argument: column family already exists: " + name)
+	}
+	cf, err := c.create(opts, name)
+	if err != nil {
+		return nil, err
+	}
+	c.add(name, cf)
+	return cf, nil
+}
+
+// Drop drops the column family and destroys its handle.
+func (c *ColumnFamilies) Drop(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cf, ok := c.handles[name]
+	if !ok {
+		return errors.New("Invalid argument: column family not found: " + name)
+	}
+	if c.drop == nil {
+		return errors.New("Not implemented: the database can't drop column families")
+	}
+	if err := c.drop(cf); err != nil {
+		return err
+	}
+	delete(c.handles, name)
+	cf.Destroy()
+	return nil
+}
+
+// nameOf returns the name the handle is tracked under, "" if it's not tracked.
+func (c *ColumnFamilies) nameOf(cf *ColumnFamilyHandle) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for name, h := range c.handles {
+		if h == cf {
+			return name
+		}
+	}
+	return ""
+}
+
+func (c *ColumnFamilies) add(name string, cf *ColumnFamilyHandle) {
+	if c.handles == nil {
+		c.handles = make(map[string]*ColumnFamilyHandle)
+	}
+	c.handles[name] = cf
+}
+
+// destroy destroys the tracked handles, before the database is closed.
+func (c *ColumnFamilies) destroy() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cf := range c.handles {
+		cf.Destroy()
+	}
+	c.handles = nil
+}
+
+// columnFamilyDescriptors returns the names and options to open the database
+// at path with: its existing column families, with the options of the
+// descriptors or opts, and the ones of the descriptors it lacks, to be
+// created once it's open.
+func columnFamilyDescriptors(opts *Options, path string, descriptors map[string]*Options) (names []string, cfOpts []*Options, missing []string, err error) {
+	existing, err := ListColumnFamilies(opts, path)
+	if isNotFound(err) {
+		// the database doesn't exist yet
+		existing, err = []string{"default"}, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	found := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		found[name] = true
+		names = append(names, name)
+		if o, ok := descriptors[name]; ok {
+			cfOpts = append(cfOpts, o)
+		} else {
+			cfOpts = append(cfOpts, opts)
+		}
+	}
+	for name := range descriptors {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return names, cfOpts, missing, nil
+}
+
+// isNotFound tells whether err is RocksDB's error for a missing file, such
+// as the CURRENT file of a database that doesn't exist.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.HasPrefix(msg, "NotFound") || strings.Contains(msg, "No such file or directory")
+}
+
+// OpenDbWithColumnFamilies opens a database with all its column families, and
+// creates the column families of the descriptors it lacks. The descriptors map
+// the names of the column families to their options, the existing column
+// families missing from it being opened with opts. The handles are tracked by
+// DB.ColumnFamilies.
+func OpenDbWithColumnFamilies(opts *Options, name string, descriptors map[string]*Options) (*DB, error) {
+	names, cfOpts, missing, err := columnFamilyDescriptors(opts, name, descriptors)
+	if err != nil {
+		return nil, err
+	}
+	db, handles, err := OpenDbColumnFamilies(opts, name, names, cfOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfs := db.ColumnFamilies()
+	for i, name := range names {
+		cfs.add(name, handles[i])
+	}
+	for _, name := range missing {
+		if _, err := cfs.Create(descriptors[name], name); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// columnFamilyName returns the name of the column family, "default" if cf is
+// nil, or "" if it's unknown: before RocksDB 7 the name is only known for the
+// handles tracked by DB.ColumnFamilies.
+func (db *DB) columnFamilyName(cf *ColumnFamilyHandle) string {
+	if cf == nil {
+		return "default"
+	}
+	if name := cf.name(); name != "" {
+		return name
+	}
+	return db.cfs.nameOf(cf)
+}
+
+// ColumnFamilies returns the registry of the column family handles of the
+// database.
+func (db *DB) ColumnFamilies() *ColumnFamilies {
+	return db.cfs.init(db.CreateColumnFamily, db.DropColumnFamily)
+}
+
+// ColumnFamilies returns the registry of the column family handles of the
+// database. The C API can't drop the column families of a TransactionDB, so
+// ColumnFamilies.Drop fails.
+func (db *TransactionDB) ColumnFamilies() *ColumnFamilies {
+	return db.cfs.init(db.CreateColumnFamily, nil)
+}
@@ -0,0 +1,65 @@
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestColumnFamiliesRegistry(t *testing.T) {
+	dir, cleanup := newTestDBDir(t, "TestColumnFamiliesRegistry")
+	defer cleanup()
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+
+	db, err := OpenDbWithColumnFamilies(opts, dir, map[string]*Options{"guide": opts})
+	ensure.Nil(t, err)
+	cfs := db.ColumnFamilies()
+	ensure.DeepEqual(t, cfs.Names(), []string{"default", "guide"})
+	ensure.True(t, cfs.CF("missing") == nil)
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.PutCF(wo, cfs.CF("guide"), []byte("hello"), []byte("world")))
+
+	_, err = cfs.Create(opts, "extra")
+	ensure.Nil(t, err)
+	_, err = cfs.Create(opts, "extra")
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, cfs.Names(), []string{"default", "extra", "guide"})
+	ensure.Nil(t, cfs.Drop("extra"))
+	ensure.NotNil(t, cfs.Drop("extra"))
+	db.Close()
+
+	// the existing column families are opened without descriptors
+	db, err = OpenDbWithColumnFamilies(opts, dir, nil)
+	ensure.Nil(t, err)
+	defer db.Close()
+	ensure.DeepEqual(t, db.ColumnFamilies().Names(), []string{"default", "guide"})
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	value, err := db.GetCF(ro, db.ColumnFamilies().CF("guide"), []byte("hello"))
+	ensure.Nil(t, err)
+	defer value.Free()
+	ensure.DeepEqual(t, value.Data(), []byte("world"))
+}
+
+func TestOpenDbWithColumnFamiliesCorrupt(t *testing.T) {
+	dir, cleanup := newTestDBDir(t, "TestOpenDbWithColumnFamiliesCorrupt")
+	defer cleanup()
+	ensure.Nil(t, ioutil.WriteFile(filepath.Join(dir, "CURRENT"), []byte("MANIFEST-000001"), 0644))
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+
+	// only a missing database is created, the other errors are returned
+	_, err := OpenDbWithColumnFamilies(opts, dir, map[string]*Options{"guide": opts})
+	ensure.NotNil(t, err)
+	ensure.StringContains(t, err.Error(), "Corruption")
+}
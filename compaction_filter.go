@@ -1,7 +1,16 @@
 package gorocksdb
 
+// #include <stdlib.h>
 // #include "rocksdb/c.h"
+//
+// extern rocksdb_compactionfilter_t* gorocksdb_compactionfilterv2_create(uintptr_t id);
 import "C"
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
 
 // A CompactionFilter can be used to filter keys during compaction time.
 type CompactionFilter interface {
@@ -71,3 +80,155 @@ func gorocksdb_compactionfilter_filter(idx int, cLevel C.int, cKey *C.char, cKey
 func gorocksdb_compactionfilter_name(idx int) *C.char {
 	return compactionFilters.Get(idx).(compactionFilterWrapper).name
 }
+
+// CompactionFilterContext describes the compaction a CompactionFilterFactory
+// creates a filter for. It holds no column family ID: the C API doesn't pass
+// it to the factories, so a factory shared by several column families can't
+// tell them apart; give each column family its own factory instead.
+type CompactionFilterContext struct {
+	// IsFullCompaction reports whether the compaction reads all the files.
+	IsFullCompaction bool
+	// IsManualCompaction reports whether the compaction was requested by
+	// the application, such as with CompactRange.
+	IsManualCompaction bool
+}
+
+// A CompactionFilterFactory creates a CompactionFilterV2 per compaction, so
+// that a filter can keep state across the keys of a compaction without
+// being shared between concurrent compactions.
+type CompactionFilterFactory interface {
+	// CreateCompactionFilter returns the filter of a new compaction.
+	CreateCompactionFilter(ctx CompactionFilterContext) CompactionFilterV2
+
+	// The name of the compaction filter factory, for logging
+	Name() string
+}
+
+// CompactionFilterDecision is the decision of a CompactionFilterV2 about a
+// key-value.
+type CompactionFilterDecision int
+
+const (
+	// CompactionFilterKeep keeps the key-value.
+	CompactionFilterKeep = CompactionFilterDecision(0)
+	// CompactionFilterRemove removes the key-value from the output of the
+	// compaction.
+	CompactionFilterRemove = CompactionFilterDecision(1)
+	// CompactionFilterChangeValue keeps the key with a new value.
+	CompactionFilterChangeValue = CompactionFilterDecision(2)
+	// CompactionFilterRemoveAndSkipUntil removes the key-value and all the
+	// following keys of the compaction before a given key, without calling
+	// the filter for them. The key must be greater than the current one,
+	// otherwise the key-value is kept.
+	CompactionFilterRemoveAndSkipUntil = CompactionFilterDecision(3)
+)
+
+// A CompactionFilterV2 is created by a CompactionFilterFactory to filter the
+// keys of a single compaction, so it is never called concurrently.
+//
+// RemoveAndSkipUntil is emulated on top of the RocksDB C API: the skipped
+// keys are still read by the compaction, but removed without calling the
+// filter. The keys are compared bytewise, so it must not be used on column
+// families with another comparator.
+type CompactionFilterV2 interface {
+	// FilterV2 decides what to do with the key-value. newVal is the new
+	// value of CompactionFilterChangeValue, and skipUntil the first key not
+	// skipped by CompactionFilterRemoveAndSkipUntil.
+	FilterV2(level int, key, val []byte) (decision CompactionFilterDecision, newVal, skipUntil []byte)
+
+	// The name of the compaction filter, for logging
+	Name() string
+}
+
+// Hold references to compaction filter factories.
+var compactionFilterFactories = NewCOWList()
+
+type compactionFilterFactoryWrapper struct {
+	name    *C.char
+	factory CompactionFilterFactory
+}
+
+func registerCompactionFilterFactory(factory CompactionFilterFactory) int {
+	return compactionFilterFactories.Append(compactionFilterFactoryWrapper{C.CString(factory.Name()), factory})
+}
+
+//export gorocksdb_compactionfilterfactory_create_filter
+func gorocksdb_compactionfilterfactory_create_filter(idx int, cCtx *C.rocksdb_compactionfiltercontext_t) *C.rocksdb_compactionfilter_t {
+	ctx := CompactionFilterContext{
+		IsFullCompaction:   C.rocksdb_compactionfiltercontext_is_full_compaction(cCtx) != 0,
+		IsManualCompaction: C.rocksdb_compactionfiltercontext_is_manual_compaction(cCtx) != 0,
+	}
+	filter := compactionFilterFactories.Get(idx).(compactionFilterFactoryWrapper).factory.CreateCompactionFilter(ctx)
+	id := atomic.AddUintptr(&nextCompactionFilterID, 1)
+	compactionFiltersV2.Store(id, &compactionFilterV2Wrapper{name: C.CString(filter.Name()), filter: filter})
+	return C.gorocksdb_compactionfilterv2_create(C.uintptr_t(id))
+}
+
+//export gorocksdb_compactionfilterfactory_name
+func gorocksdb_compactionfilterfactory_name(idx int) *C.char {
+	return compactionFilterFactories.Get(idx).(compactionFilterFactoryWrapper).name
+}
+
+// Hold references to the filters created by the factories, until the
+// compaction is done.
+var (
+	compactionFiltersV2    sync.Map
+	nextCompactionFilterID uintptr
+)
+
+type compactionFilterV2Wrapper struct {
+	name      *C.char
+	filter    CompactionFilterV2
+	skipUntil []byte
+	// newVal is the last value changed by the filter, which RocksDB copies
+	// but doesn't free.
+	newVal *C.char
+}
+
+func getCompactionFilterV2(id uintptr) *compactionFilterV2Wrapper {
+	w, _ := compactionFiltersV2.Load(id)
+	return w.(*compactionFilterV2Wrapper)
+}
+
+//export gorocksdb_compactionfilterv2_filter
+func gorocksdb_compactionfilterv2_filter(id uintptr, cLevel C.int, cKey *C.char, cKeyLen C.size_t, cVal *C.char, cValLen C.size_t, cNewVal **C.char, cNewValLen *C.size_t, cValChanged *C.uchar) C.uchar {
+	w := getCompactionFilterV2(id)
+	key := charToByte(cKey, cKeyLen)
+	if w.skipUntil != nil {
+		if bytes.Compare(key, w.skipUntil) < 0 {
+			return boolToChar(true)
+		}
+		w.skipUntil = nil
+	}
+
+	decision, newVal, skipUntil := w.filter.FilterV2(int(cLevel), key, charToByte(cVal, cValLen))
+	switch decision {
+	case CompactionFilterRemove:
+		return boolToChar(true)
+	case CompactionFilterChangeValue:
+		C.free(unsafe.Pointer(w.newVal))
+		w.newVal = cByteSlice(newVal)
+		*cNewVal = w.newVal
+		*cNewValLen = C.size_t(len(newVal))
+		*cValChanged = boolToChar(true)
+	case CompactionFilterRemoveAndSkipUntil:
+		if bytes.Compare(key, skipUntil) < 0 {
+			w.skipUntil = copyBytes(skipUntil)
+			return boolToChar(true)
+		}
+	}
+	return boolToChar(false)
+}
+
+//export gorocksdb_compactionfilterv2_name
+func gorocksdb_compactionfilterv2_name(id uintptr) *C.char {
+	return getCompactionFilterV2(id).name
+}
+
+//export gorocksdb_compactionfilterv2_destruct
+func gorocksdb_compactionfilterv2_destruct(id uintptr) {
+	w := getCompactionFilterV2(id)
+	compactionFiltersV2.Delete(id)
+	C.free(unsafe.Pointer(w.name))
+	C.free(unsafe.Pointer(w.newVal))
+}
@@ -62,3 +62,56 @@ func (m *mockCompactionFilter) Name() string { return "gorocksdb.test" }
 func (m *mockCompactionFilter) Filter(level int, key, val []byte) (bool, []byte) {
 	return m.filter(level, key, val)
 }
+
+func TestCompactionFilterFactory(t *testing.T) {
+	factory := &mockCompactionFilterFactory{}
+	db := newTestDB(t, "TestCompactionFilterFactory", func(opts *Options) {
+		opts.SetCompactionFilterFactory(factory)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, key := range []string{"a1", "a2", "a3", "b1", "c1"} {
+		ensure.Nil(t, db.Put(wo, []byte(key), []byte("old")))
+	}
+
+	// trigger a compaction
+	db.CompactRange(Range{nil, nil})
+	ensure.DeepEqual(t, factory.contexts, []CompactionFilterContext{{IsFullCompaction: true, IsManualCompaction: true}})
+	// the keys skipped are not passed to the filter
+	ensure.DeepEqual(t, factory.filtered, []string{"a1", "b1", "c1"})
+
+	ro := NewDefaultReadOptions()
+	for key, expected := range map[string][]byte{"a1": nil, "a2": nil, "a3": nil, "b1": []byte("old"), "c1": []byte("new")} {
+		v, err := db.GetBytes(ro, []byte(key))
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, v, expected, key)
+	}
+}
+
+type mockCompactionFilterFactory struct {
+	contexts []CompactionFilterContext
+	filtered []string
+}
+
+func (m *mockCompactionFilterFactory) Name() string { return "gorocksdb.test" }
+func (m *mockCompactionFilterFactory) CreateCompactionFilter(ctx CompactionFilterContext) CompactionFilterV2 {
+	m.contexts = append(m.contexts, ctx)
+	return &mockCompactionFilterV2{factory: m}
+}
+
+type mockCompactionFilterV2 struct {
+	factory *mockCompactionFilterFactory
+}
+
+func (m *mockCompactionFilterV2) Name() string { return "gorocksdb.test" }
+func (m *mockCompactionFilterV2) FilterV2(level int, key, val []byte) (CompactionFilterDecision, []byte, []byte) {
+	m.factory.filtered = append(m.factory.filtered, string(key))
+	switch string(key) {
+	case "a1":
+		return CompactionFilterRemoveAndSkipUntil, nil, []byte("b")
+	case "c1":
+		return CompactionFilterChangeValue, []byte("new"), nil
+	}
+	return CompactionFilterKeep, nil, nil
+}
@@ -1,10 +1,26 @@
 package gorocksdb
 
 // #include "rocksdb/c.h"
+//
+// extern rocksdb_comparator_t* gorocksdb_reverse_bytewise_comparator_create();
+// extern rocksdb_comparator_t* gorocksdb_uint64_little_endian_comparator_create();
 import "C"
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/flier/gorocksdb/keys"
+)
 
 // A Comparator object provides a total order across slices that are
 // used as keys in an sstable or a database.
+//
+// Besides the default bytewise comparator, the package provides the native
+// comparators NewReverseBytewiseComparator and
+// NewUint64LittleEndianComparator, and TupleComparator. There is no
+// comparator for big-endian integer keys: their bytewise order is their
+// numeric order, so the default comparator orders them, without the cost of
+// a custom one.
 type Comparator interface {
 	// Three-way comparison. Returns value:
 	//   < 0 iff "a" < "b",
@@ -18,15 +34,120 @@ type Comparator interface {
 
 // NewNativeComparator creates a Comparator object.
 func NewNativeComparator(c *C.rocksdb_comparator_t) Comparator {
-	return nativeComparator{c}
+	return nativeComparator{c: c}
+}
+
+// NewReverseBytewiseComparator creates a native comparator ordering the keys
+// in reverse bytewise order, compatible with RocksDB's
+// ReverseBytewiseComparator. Comparisons don't call back into Go.
+//
+// Like any native comparator, it is freed with the Options it is set on, so
+// it must not be set on several Options.
+func NewReverseBytewiseComparator() Comparator {
+	return nativeComparator{
+		c:       C.gorocksdb_reverse_bytewise_comparator_create(),
+		name:    "rocksdb.ReverseBytewiseComparator",
+		compare: ReverseBytewiseCompare,
+	}
+}
+
+// NewUint64LittleEndianComparator creates a native comparator ordering
+// 8-byte keys as little-endian unsigned integers, and other keys bytewise.
+// Comparisons don't call back into Go. Big-endian keys don't need it, since
+// their bytewise order is already their numeric order.
+//
+// Like any native comparator, it is freed with the Options it is set on, so
+// it must not be set on several Options.
+func NewUint64LittleEndianComparator() Comparator {
+	return nativeComparator{
+		c:       C.gorocksdb_uint64_little_endian_comparator_create(),
+		name:    "gorocksdb.Uint64LittleEndianComparator",
+		compare: Uint64LittleEndianCompare,
+	}
 }
 
 type nativeComparator struct {
 	c *C.rocksdb_comparator_t
+
+	// name and compare mirror the built-in comparators in Go.
+	name    string
+	compare func(a, b []byte) int
+}
+
+func (c nativeComparator) Compare(a, b []byte) int {
+	if c.compare == nil {
+		return 0
+	}
+	return c.compare(a, b)
+}
+func (c nativeComparator) Name() string { return c.name }
+
+// ReverseBytewiseCompare orders the keys in reverse bytewise order.
+func ReverseBytewiseCompare(a, b []byte) int {
+	return bytes.Compare(b, a)
+}
+
+// Uint64LittleEndianCompare orders 8-byte keys as little-endian unsigned
+// integers, and other keys bytewise.
+func Uint64LittleEndianCompare(a, b []byte) int {
+	if len(a) != 8 || len(b) != 8 {
+		return bytes.Compare(a, b)
+	}
+	x, y := binary.LittleEndian.Uint64(a), binary.LittleEndian.Uint64(b)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// TupleComparator orders the keys packed by keys.Pack element by element,
+// each element with its own comparison function. The function is passed the
+// packed elements, which compare bytewise like their values, so that it can
+// for instance reverse their order; keys.Unpack decodes them. The elements
+// beyond the comparison functions are compared bytewise, and a tuple sorts
+// before the tuples it is a prefix of.
+//
+// Since it is a Go comparator, every comparison calls back into Go. Keys
+// that can be ordered by the encoding itself, using keys.Desc for the
+// descending elements, should rather use the default comparator.
+type TupleComparator struct {
+	name   string
+	fields []func(a, b []byte) int
 }
 
-func (c nativeComparator) Compare(a, b []byte) int { return 0 }
-func (c nativeComparator) Name() string            { return "" }
+// NewTupleComparator creates a TupleComparator comparing the elements of the
+// keys with the given functions, nil meaning bytes.Compare. The name must
+// change whenever the order changes.
+func NewTupleComparator(name string, fields ...func(a, b []byte) int) *TupleComparator {
+	return &TupleComparator{name: name, fields: fields}
+}
+
+// Name implements Comparator.
+func (c *TupleComparator) Name() string { return c.name }
+
+// Compare implements Comparator. Malformed keys are compared bytewise from
+// the first malformed element.
+func (c *TupleComparator) Compare(a, b []byte) int {
+	for i := 0; len(a) > 0 && len(b) > 0; i++ {
+		na, errA := keys.PrefixLen(a, 1)
+		nb, errB := keys.PrefixLen(b, 1)
+		if errA != nil || errB != nil {
+			return bytes.Compare(a, b)
+		}
+		compare := bytes.Compare
+		if i < len(c.fields) && c.fields[i] != nil {
+			compare = c.fields[i]
+		}
+		if r := compare(a[:na:na], b[:nb:nb]); r != 0 {
+			return r
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return len(a) - len(b)
+}
 
 // Hold references to comperators.
 var comperators = NewCOWList()
@@ -2,9 +2,11 @@ package gorocksdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 
 	"github.com/facebookgo/ensure"
+	"github.com/flier/gorocksdb/keys"
 )
 
 func TestComparator(t *testing.T) {
@@ -45,3 +47,82 @@ func (cmp *bytesReverseComparator) Name() string { return "gorocksdb.bytes-rever
 func (cmp *bytesReverseComparator) Compare(a, b []byte) int {
 	return bytes.Compare(a, b) * -1
 }
+
+func TestNativeComparators(t *testing.T) {
+	for _, c := range []struct {
+		cmp       func() Comparator
+		givenKeys [][]byte
+	}{
+		{NewReverseBytewiseComparator, [][]byte{[]byte("key3"), []byte("key2"), []byte("key10"), []byte("key1")}},
+		{NewUint64LittleEndianComparator, [][]byte{{1, 0, 0, 0, 0, 0, 0, 0}, {0, 1, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 0, 0, 0, 1}}},
+	} {
+		cmp := c.cmp()
+		db := newTestDB(t, "TestNativeComparators", func(opts *Options) {
+			opts.SetComparator(cmp)
+		})
+
+		wo := NewDefaultWriteOptions()
+		for i := len(c.givenKeys) - 1; i >= 0; i-- {
+			ensure.Nil(t, db.Put(wo, c.givenKeys[i], []byte("val")))
+		}
+
+		iter := db.NewIterator(NewDefaultReadOptions())
+		var actualKeys [][]byte
+		for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+			actualKeys = append(actualKeys, copyBytes(iter.KeyData()))
+		}
+		ensure.Nil(t, iter.Err())
+		iter.Close()
+		db.Close()
+
+		ensure.DeepEqual(t, actualKeys, c.givenKeys, cmp.Name())
+		for i := 1; i < len(c.givenKeys); i++ {
+			ensure.True(t, cmp.Compare(c.givenKeys[i-1], c.givenKeys[i]) < 0, cmp.Name())
+		}
+	}
+}
+
+func TestTupleComparator(t *testing.T) {
+	cmp := NewTupleComparator("gorocksdb.test-tuple", nil, ReverseBytewiseCompare)
+	givenKeys := [][]byte{
+		keys.MustPack("a"),
+		keys.MustPack("a", "z"),
+		keys.MustPack("a", "b"),
+		keys.MustPack("a", "b", "c"),
+		keys.MustPack("ab", "z"),
+	}
+	for i := 1; i < len(givenKeys); i++ {
+		ensure.True(t, cmp.Compare(givenKeys[i-1], givenKeys[i]) < 0, i)
+		ensure.True(t, cmp.Compare(givenKeys[i], givenKeys[i-1]) > 0, i)
+	}
+	ensure.DeepEqual(t, cmp.Compare(givenKeys[2], keys.MustPack("a", "b")), 0)
+
+	// malformed keys are compared bytewise
+	ensure.True(t, cmp.Compare([]byte{0x02, 'a'}, []byte{0x02, 'b'}) < 0)
+}
+
+func benchmarkComparator(b *testing.B, cmp Comparator) {
+	db := newTestDB(b, "BenchmarkComparator", func(opts *Options) {
+		opts.SetComparator(cmp)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	wo.DisableWAL(true)
+	key := make([]byte, 8)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		binary.BigEndian.PutUint64(key, uint64(i*7919%b.N))
+		if err := db.Put(wo, key, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkComparatorGo(b *testing.B) {
+	benchmarkComparator(b, &bytesReverseComparator{})
+}
+
+func BenchmarkComparatorNative(b *testing.B) {
+	benchmarkComparator(b, NewReverseBytewiseComparator())
+}
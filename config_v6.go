@@ -0,0 +1,659 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Config describes Options declaratively, as a plain struct that can be
+// decoded from JSON or YAML. The fields are named after the RocksDB options
+// they set, and a nil or empty field leaves the option to its default.
+//
+//	cfg := gorocksdb.Config{}
+//	err := json.Unmarshal(data, &cfg)
+//	...
+//	opts, err := cfg.Build()
+type Config struct {
+	// DB holds the database wide options.
+	DB DBConfig `json:"db" yaml:"db"`
+	// ColumnFamily holds the options of the default column family, which are
+	// also the defaults of the other column families.
+	ColumnFamily ColumnFamilyConfig `json:"column_family" yaml:"column_family"`
+	// ColumnFamilies holds the options of the column families by name,
+	// overriding the ones of ColumnFamily.
+	ColumnFamilies map[string]ColumnFamilyConfig `json:"column_families,omitempty" yaml:"column_families,omitempty"`
+}
+
+// DBConfig describes the database wide options.
+type DBConfig struct {
+	CreateIfMissing                  *bool   `json:"create_if_missing,omitempty" yaml:"create_if_missing,omitempty"`
+	CreateMissingColumnFamilies      *bool   `json:"create_missing_column_families,omitempty" yaml:"create_missing_column_families,omitempty"`
+	ErrorIfExists                    *bool   `json:"error_if_exists,omitempty" yaml:"error_if_exists,omitempty"`
+	ParanoidChecks                   *bool   `json:"paranoid_checks,omitempty" yaml:"paranoid_checks,omitempty"`
+	MaxOpenFiles                     *int    `json:"max_open_files,omitempty" yaml:"max_open_files,omitempty"`
+	MaxBackgroundJobs                *int    `json:"max_background_jobs,omitempty" yaml:"max_background_jobs,omitempty"`
+	MaxSubcompactions                *uint   `json:"max_subcompactions,omitempty" yaml:"max_subcompactions,omitempty"`
+	MaxTotalWalSize                  *uint64 `json:"max_total_wal_size,omitempty" yaml:"max_total_wal_size,omitempty"`
+	DbWriteBufferSize                *int    `json:"db_write_buffer_size,omitempty" yaml:"db_write_buffer_size,omitempty"`
+	WalDir                           string  `json:"wal_dir,omitempty" yaml:"wal_dir,omitempty"`
+	DbLogDir                         string  `json:"db_log_dir,omitempty" yaml:"db_log_dir,omitempty"`
+	InfoLogLevel                     string  `json:"info_log_level,omitempty" yaml:"info_log_level,omitempty"`
+	KeepLogFileNum                   *int    `json:"keep_log_file_num,omitempty" yaml:"keep_log_file_num,omitempty"`
+	BytesPerSync                     *uint64 `json:"bytes_per_sync,omitempty" yaml:"bytes_per_sync,omitempty"`
+	WalBytesPerSync                  *uint64 `json:"wal_bytes_per_sync,omitempty" yaml:"wal_bytes_per_sync,omitempty"`
+	UseFsync                         *bool   `json:"use_fsync,omitempty" yaml:"use_fsync,omitempty"`
+	AllowMmapReads                   *bool   `json:"allow_mmap_reads,omitempty" yaml:"allow_mmap_reads,omitempty"`
+	AllowMmapWrites                  *bool   `json:"allow_mmap_writes,omitempty" yaml:"allow_mmap_writes,omitempty"`
+	UseDirectReads                   *bool   `json:"use_direct_reads,omitempty" yaml:"use_direct_reads,omitempty"`
+	UseDirectIOForFlushAndCompaction *bool   `json:"use_direct_io_for_flush_and_compaction,omitempty" yaml:"use_direct_io_for_flush_and_compaction,omitempty"`
+	EnablePipelinedWrite             *bool   `json:"enable_pipelined_write,omitempty" yaml:"enable_pipelined_write,omitempty"`
+	UnorderedWrite                   *bool   `json:"unordered_write,omitempty" yaml:"unordered_write,omitempty"`
+	AtomicFlush                      *bool   `json:"atomic_flush,omitempty" yaml:"atomic_flush,omitempty"`
+	StatsDumpPeriodSec               *uint   `json:"stats_dump_period_sec,omitempty" yaml:"stats_dump_period_sec,omitempty"`
+}
+
+// ColumnFamilyConfig describes the options of a column family.
+type ColumnFamilyConfig struct {
+	WriteBufferSize                  *int     `json:"write_buffer_size,omitempty" yaml:"write_buffer_size,omitempty"`
+	MaxWriteBufferNumber             *int     `json:"max_write_buffer_number,omitempty" yaml:"max_write_buffer_number,omitempty"`
+	MinWriteBufferNumberToMerge      *int     `json:"min_write_buffer_number_to_merge,omitempty" yaml:"min_write_buffer_number_to_merge,omitempty"`
+	NumLevels                        *int     `json:"num_levels,omitempty" yaml:"num_levels,omitempty"`
+	Level0FileNumCompactionTrigger   *int     `json:"level0_file_num_compaction_trigger,omitempty" yaml:"level0_file_num_compaction_trigger,omitempty"`
+	Level0SlowdownWritesTrigger      *int     `json:"level0_slowdown_writes_trigger,omitempty" yaml:"level0_slowdown_writes_trigger,omitempty"`
+	Level0StopWritesTrigger          *int     `json:"level0_stop_writes_trigger,omitempty" yaml:"level0_stop_writes_trigger,omitempty"`
+	TargetFileSizeBase               *uint64  `json:"target_file_size_base,omitempty" yaml:"target_file_size_base,omitempty"`
+	TargetFileSizeMultiplier         *int     `json:"target_file_size_multiplier,omitempty" yaml:"target_file_size_multiplier,omitempty"`
+	MaxBytesForLevelBase             *uint64  `json:"max_bytes_for_level_base,omitempty" yaml:"max_bytes_for_level_base,omitempty"`
+	MaxBytesForLevelMultiplier       *float64 `json:"max_bytes_for_level_multiplier,omitempty" yaml:"max_bytes_for_level_multiplier,omitempty"`
+	LevelCompactionDynamicLevelBytes *bool    `json:"level_compaction_dynamic_level_bytes,omitempty" yaml:"level_compaction_dynamic_level_bytes,omitempty"`
+	DisableAutoCompactions           *bool    `json:"disable_auto_compactions,omitempty" yaml:"disable_auto_compactions,omitempty"`
+	OptimizeFiltersForHits           *bool    `json:"optimize_filters_for_hits,omitempty" yaml:"optimize_filters_for_hits,omitempty"`
+	MaxSuccessiveMerges              *int     `json:"max_successive_merges,omitempty" yaml:"max_successive_merges,omitempty"`
+	MemtablePrefixBloomSizeRatio     *float64 `json:"memtable_prefix_bloom_size_ratio,omitempty" yaml:"memtable_prefix_bloom_size_ratio,omitempty"`
+	// FixedPrefixLength sets a fixed prefix extractor of that length.
+	FixedPrefixLength *int `json:"fixed_prefix_length,omitempty" yaml:"fixed_prefix_length,omitempty"`
+
+	// Compression is one of "none", "snappy", "zlib", "bz2", "lz4", "lz4hc",
+	// "xpress" or "zstd", as are the values of CompressionPerLevel and
+	// BottommostCompression.
+	Compression           string             `json:"compression,omitempty" yaml:"compression,omitempty"`
+	CompressionPerLevel   []string           `json:"compression_per_level,omitempty" yaml:"compression_per_level,omitempty"`
+	BottommostCompression string             `json:"bottommost_compression,omitempty" yaml:"bottommost_compression,omitempty"`
+	CompressionOptions    *CompressionConfig `json:"compression_options,omitempty" yaml:"compression_options,omitempty"`
+
+	// CompactionStyle is one of "level", "universal" or "fifo". Universal and
+	// FIFO can only be set with the matching style.
+	CompactionStyle string                     `json:"compaction_style,omitempty" yaml:"compaction_style,omitempty"`
+	Universal       *UniversalCompactionConfig `json:"universal,omitempty" yaml:"universal,omitempty"`
+	FIFO            *FIFOCompactionConfig      `json:"fifo,omitempty" yaml:"fifo,omitempty"`
+
+	// Table configures the block based table factory.
+	Table *BlockBasedTableConfig `json:"table,omitempty" yaml:"table,omitempty"`
+}
+
+// CompressionConfig describes CompressionOptions, a nil field keeping the
+// value of NewDefaultCompressionOptions.
+type CompressionConfig struct {
+	WindowBits        *int `json:"window_bits,omitempty" yaml:"window_bits,omitempty"`
+	Level             *int `json:"level,omitempty" yaml:"level,omitempty"`
+	Strategy          *int `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	MaxDictBytes      *int `json:"max_dict_bytes,omitempty" yaml:"max_dict_bytes,omitempty"`
+	ZstdMaxTrainBytes *int `json:"zstd_max_train_bytes,omitempty" yaml:"zstd_max_train_bytes,omitempty"`
+}
+
+// UniversalCompactionConfig describes UniversalCompactionOptions.
+type UniversalCompactionConfig struct {
+	SizeRatio                   *uint `json:"size_ratio,omitempty" yaml:"size_ratio,omitempty"`
+	MinMergeWidth               *uint `json:"min_merge_width,omitempty" yaml:"min_merge_width,omitempty"`
+	MaxMergeWidth               *uint `json:"max_merge_width,omitempty" yaml:"max_merge_width,omitempty"`
+	MaxSizeAmplificationPercent *uint `json:"max_size_amplification_percent,omitempty" yaml:"max_size_amplification_percent,omitempty"`
+	CompressionSizePercent      *int  `json:"compression_size_percent,omitempty" yaml:"compression_size_percent,omitempty"`
+	// StopStyle is either "similar_size" or "total_size".
+	StopStyle string `json:"stop_style,omitempty" yaml:"stop_style,omitempty"`
+}
+
+// FIFOCompactionConfig describes FIFOCompactionOptions.
+type FIFOCompactionConfig struct {
+	MaxTableFilesSize *uint64 `json:"max_table_files_size,omitempty" yaml:"max_table_files_size,omitempty"`
+}
+
+// BlockBasedTableConfig describes BlockBasedTableOptions.
+type BlockBasedTableConfig struct {
+	BlockSize                        *int  `json:"block_size,omitempty" yaml:"block_size,omitempty"`
+	BlockSizeDeviation               *int  `json:"block_size_deviation,omitempty" yaml:"block_size_deviation,omitempty"`
+	BlockRestartInterval             *int  `json:"block_restart_interval,omitempty" yaml:"block_restart_interval,omitempty"`
+	CacheIndexAndFilterBlocks        *bool `json:"cache_index_and_filter_blocks,omitempty" yaml:"cache_index_and_filter_blocks,omitempty"`
+	PinL0FilterAndIndexBlocksInCache *bool `json:"pin_l0_filter_and_index_blocks_in_cache,omitempty" yaml:"pin_l0_filter_and_index_blocks_in_cache,omitempty"`
+	WholeKeyFiltering                *bool `json:"whole_key_filtering,omitempty" yaml:"whole_key_filtering,omitempty"`
+	// BlockCacheSize sets a LRU block cache of that capacity, in bytes.
+	BlockCacheSize *int  `json:"block_cache_size,omitempty" yaml:"block_cache_size,omitempty"`
+	NoBlockCache   *bool `json:"no_block_cache,omitempty" yaml:"no_block_cache,omitempty"`
+	// BloomFilterBitsPerKey sets a full bloom filter policy.
+	BloomFilterBitsPerKey *int `json:"bloom_filter_bits_per_key,omitempty" yaml:"bloom_filter_bits_per_key,omitempty"`
+	// IndexType is one of "binary_search", "hash_search" or
+	// "two_level_index_search". The hash search needs a prefix extractor.
+	IndexType string `json:"index_type,omitempty" yaml:"index_type,omitempty"`
+	// DataBlockIndexType is either "binary_search" or "binary_search_and_hash".
+	// DataBlockHashRatio can only be set with the latter.
+	DataBlockIndexType string   `json:"data_block_index_type,omitempty" yaml:"data_block_index_type,omitempty"`
+	DataBlockHashRatio *float64 `json:"data_block_hash_ratio,omitempty" yaml:"data_block_hash_ratio,omitempty"`
+}
+
+// ConfigError reports an invalid field of a Config.
+type ConfigError struct {
+	// Field is the path of the field, using the JSON names.
+	Field string
+	Msg   string
+}
+
+func (e *ConfigError) Error() string {
+	return e.Field + ": " + e.Msg
+}
+
+// ConfigErrors lists the invalid fields of a Config.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "Invalid argument: " + strings.Join(msgs, "; ")
+}
+
+var (
+	compressionTypesByName = map[string]CompressionType{
+		"none":   NoCompression,
+		"snappy": SnappyCompression,
+		"zlib":   ZLibCompression,
+		"bz2":    Bz2Compression,
+		"lz4":    LZ4Compression,
+		"lz4hc":  LZ4HCCompression,
+		"xpress": XpressCompression,
+		"zstd":   ZSTDCompression,
+	}
+	compactionStylesByName = map[string]CompactionStyle{
+		"level":     LevelCompactionStyle,
+		"universal": UniversalCompactionStyle,
+		"fifo":      FIFOCompactionStyle,
+	}
+	infoLogLevelsByName = map[string]InfoLogLevel{
+		"debug": DebugInfoLogLevel,
+		"info":  InfoInfoLogLevel,
+		"warn":  WarnInfoLogLevel,
+		"error": ErrorInfoLogLevel,
+		"fatal": FatalInfoLogLevel,
+	}
+	stopStylesByName = map[string]UniversalCompactionStopStyle{
+		"similar_size": CompactionStopStyleSimilarSize,
+		"total_size":   CompactionStopStyleTotalSize,
+	}
+	indexTypesByName = map[string]IndexType{
+		"binary_search":          KBinarySearchIndexType,
+		"hash_search":            KHashSearchIndexType,
+		"two_level_index_search": KTwoLevelIndexSearchIndexType,
+	}
+	dataBlockIndexTypesByName = map[string]DataBlockIndexType{
+		"binary_search":          KDataBlockBinarySearch,
+		"binary_search_and_hash": KDataBlockBinaryAndHash,
+	}
+)
+
+// Validate checks the values of the config and the combinations of options
+// RocksDB would reject or ignore, returning ConfigErrors if any.
+func (cfg *Config) Validate() error {
+	var errs ConfigErrors
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Field: field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	db := &cfg.DB
+	if db.InfoLogLevel != "" {
+		if _, ok := infoLogLevelsByName[db.InfoLogLevel]; !ok {
+			fail("db.info_log_level", "unknown level %q", db.InfoLogLevel)
+		}
+	}
+	if isTrue(db.UseDirectReads) && isTrue(db.AllowMmapReads) {
+		fail("db.use_direct_reads", "can't be set with allow_mmap_reads")
+	}
+	if isTrue(db.UseDirectIOForFlushAndCompaction) && isTrue(db.AllowMmapWrites) {
+		fail("db.use_direct_io_for_flush_and_compaction", "can't be set with allow_mmap_writes")
+	}
+	if isTrue(db.UnorderedWrite) && isTrue(db.EnablePipelinedWrite) {
+		fail("db.unordered_write", "can't be set with enable_pipelined_write")
+	}
+
+	cfg.ColumnFamily.validate("column_family", fail)
+	for _, name := range cfg.ColumnFamilyNames()[1:] {
+		if name == "" {
+			fail("column_families", "a column family has no name")
+			continue
+		}
+		cf := cfg.columnFamily(name)
+		cf.validate("column_families."+name, fail)
+	}
+	if _, ok := cfg.ColumnFamilies["default"]; ok {
+		cf := cfg.columnFamily("default")
+		cf.validate("column_families.default", fail)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (cf *ColumnFamilyConfig) validate(field string, fail func(field, format string, args ...interface{})) {
+	positive := func(name string, value *int) {
+		if value != nil && *value <= 0 {
+			fail(field+"."+name, "must be positive")
+		}
+	}
+	positive("write_buffer_size", cf.WriteBufferSize)
+	positive("max_write_buffer_number", cf.MaxWriteBufferNumber)
+	positive("num_levels", cf.NumLevels)
+	positive("fixed_prefix_length", cf.FixedPrefixLength)
+	if cf.MaxBytesForLevelMultiplier != nil && *cf.MaxBytesForLevelMultiplier <= 0 {
+		fail(field+".max_bytes_for_level_multiplier", "must be positive")
+	}
+
+	if a, b := cf.Level0FileNumCompactionTrigger, cf.Level0SlowdownWritesTrigger; a != nil && b != nil && *a > *b {
+		fail(field+".level0_slowdown_writes_trigger", "must not be lower than level0_file_num_compaction_trigger")
+	}
+	if a, b := cf.Level0SlowdownWritesTrigger, cf.Level0StopWritesTrigger; a != nil && b != nil && *a > *b {
+		fail(field+".level0_stop_writes_trigger", "must not be lower than level0_slowdown_writes_trigger")
+	}
+
+	checkCompression := func(name, value string) {
+		if _, ok := compressionTypesByName[value]; value != "" && !ok {
+			fail(field+"."+name, "unknown compression %q", value)
+		}
+	}
+	checkCompression("compression", cf.Compression)
+	checkCompression("bottommost_compression", cf.BottommostCompression)
+	for i, value := range cf.CompressionPerLevel {
+		checkCompression(fmt.Sprintf("compression_per_level[%d]", i), value)
+	}
+	numLevels := 7
+	if cf.NumLevels != nil {
+		numLevels = *cf.NumLevels
+	}
+	if len(cf.CompressionPerLevel) > numLevels {
+		fail(field+".compression_per_level", "has %d levels, more than num_levels (%d)", len(cf.CompressionPerLevel), numLevels)
+	}
+
+	style := cf.CompactionStyle
+	if _, ok := compactionStylesByName[style]; style != "" && !ok {
+		fail(field+".compaction_style", "unknown style %q", style)
+	}
+	if cf.Universal != nil && style != "universal" {
+		fail(field+".universal", "needs the universal compaction_style")
+	}
+	if cf.Universal != nil && cf.Universal.StopStyle != "" {
+		if _, ok := stopStylesByName[cf.Universal.StopStyle]; !ok {
+			fail(field+".universal.stop_style", "unknown style %q", cf.Universal.StopStyle)
+		}
+	}
+	if u := cf.Universal; u != nil && u.MinMergeWidth != nil && u.MaxMergeWidth != nil && *u.MinMergeWidth > *u.MaxMergeWidth {
+		fail(field+".universal.max_merge_width", "must not be lower than min_merge_width")
+	}
+	if cf.FIFO != nil && style != "fifo" {
+		fail(field+".fifo", "needs the fifo compaction_style")
+	}
+
+	if cf.MemtablePrefixBloomSizeRatio != nil && cf.FixedPrefixLength == nil {
+		fail(field+".memtable_prefix_bloom_size_ratio", "needs a fixed_prefix_length")
+	}
+
+	if t := cf.Table; t != nil {
+		if isTrue(t.NoBlockCache) && t.BlockCacheSize != nil {
+			fail(field+".table.block_cache_size", "can't be set with no_block_cache")
+		}
+		positive("table.block_size", t.BlockSize)
+		positive("table.bloom_filter_bits_per_key", t.BloomFilterBitsPerKey)
+		if _, ok := indexTypesByName[t.IndexType]; t.IndexType != "" && !ok {
+			fail(field+".table.index_type", "unknown type %q", t.IndexType)
+		}
+		if t.IndexType == "hash_search" && cf.FixedPrefixLength == nil {
+			fail(field+".table.index_type", "hash_search needs a fixed_prefix_length")
+		}
+		if _, ok := dataBlockIndexTypesByName[t.DataBlockIndexType]; t.DataBlockIndexType != "" && !ok {
+			fail(field+".table.data_block_index_type", "unknown type %q", t.DataBlockIndexType)
+		}
+		if t.DataBlockHashRatio != nil && t.DataBlockIndexType != "binary_search_and_hash" {
+			fail(field+".table.data_block_hash_ratio", "needs the binary_search_and_hash data_block_index_type")
+		}
+	}
+}
+
+// ColumnFamilyNames returns the names of the configured column families,
+// "default" first and the others in name order.
+func (cfg *Config) ColumnFamilyNames() []string {
+	names := []string{"default"}
+	for name := range cfg.ColumnFamilies {
+		if name != "default" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Build validates the config and creates the Options of the database and of
+// its default column family, ready for OpenDb.
+func (cfg *Config) Build() (*Options, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	opts := NewDefaultOptions()
+	cfg.DB.apply(opts)
+	cf := cfg.columnFamily("default")
+	cf.apply(opts)
+	return opts, nil
+}
+
+// BuildColumnFamilies validates the config and creates the Options of the
+// database and of all its column families, in the order of
+// ColumnFamilyNames, ready for OpenDbColumnFamilies.
+func (cfg *Config) BuildColumnFamilies() (*Options, []string, []*Options, error) {
+	opts, err := cfg.Build()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	names := cfg.ColumnFamilyNames()
+	cfOpts := make([]*Options, len(names))
+	for i, name := range names {
+		cf := cfg.columnFamily(name)
+		cfOpts[i] = NewDefaultOptions()
+		cf.apply(cfOpts[i])
+	}
+	return opts, names, cfOpts, nil
+}
+
+// columnFamily returns the options of the column family, the fields it
+// doesn't set taken from ColumnFamily. The nested sections are replaced as a
+// whole.
+func (cfg *Config) columnFamily(name string) ColumnFamilyConfig {
+	cf := cfg.ColumnFamily
+	override, ok := cfg.ColumnFamilies[name]
+	if !ok {
+		return cf
+	}
+	// the fields are pointers, slices, strings and nested sections, so the
+	// ones set are the non-zero ones
+	dst, src := reflect.ValueOf(&cf).Elem(), reflect.ValueOf(override)
+	for i := 0; i < src.NumField(); i++ {
+		if field := src.Field(i); !field.IsZero() {
+			dst.Field(i).Set(field)
+		}
+	}
+	return cf
+}
+
+func (db *DBConfig) apply(opts *Options) {
+	if db.CreateIfMissing != nil {
+		opts.SetCreateIfMissing(*db.CreateIfMissing)
+	}
+	if db.CreateMissingColumnFamilies != nil {
+		opts.SetCreateIfMissingColumnFamilies(*db.CreateMissingColumnFamilies)
+	}
+	if db.ErrorIfExists != nil {
+		opts.SetErrorIfExists(*db.ErrorIfExists)
+	}
+	if db.ParanoidChecks != nil {
+		opts.SetParanoidChecks(*db.ParanoidChecks)
+	}
+	if db.MaxOpenFiles != nil {
+		opts.SetMaxOpenFiles(*db.MaxOpenFiles)
+	}
+	if db.MaxBackgroundJobs != nil {
+		opts.SetMaxBackgroundJobs(*db.MaxBackgroundJobs)
+	}
+	if db.MaxSubcompactions != nil {
+		opts.SetMaxSubcompactions(*db.MaxSubcompactions)
+	}
+	if db.MaxTotalWalSize != nil {
+		opts.SetMaxTotalWalSize(*db.MaxTotalWalSize)
+	}
+	if db.DbWriteBufferSize != nil {
+		opts.SetDbWriteBufferSize(*db.DbWriteBufferSize)
+	}
+	if db.WalDir != "" {
+		opts.SetWalDir(db.WalDir)
+	}
+	if db.DbLogDir != "" {
+		opts.SetDbLogDir(db.DbLogDir)
+	}
+	if db.InfoLogLevel != "" {
+		opts.SetInfoLogLevel(infoLogLevelsByName[db.InfoLogLevel])
+	}
+	if db.KeepLogFileNum != nil {
+		opts.SetKeepLogFileNum(*db.KeepLogFileNum)
+	}
+	if db.BytesPerSync != nil {
+		opts.SetBytesPerSync(*db.BytesPerSync)
+	}
+	if db.WalBytesPerSync != nil {
+		opts.SetWalBytesPerSync(*db.WalBytesPerSync)
+	}
+	if db.UseFsync != nil {
+		opts.SetUseFsync(*db.UseFsync)
+	}
+	if db.AllowMmapReads != nil {
+		opts.SetAllowMmapReads(*db.AllowMmapReads)
+	}
+	if db.AllowMmapWrites != nil {
+		opts.SetAllowMmapWrites(*db.AllowMmapWrites)
+	}
+	if db.UseDirectReads != nil {
+		opts.SetUseDirectReads(*db.UseDirectReads)
+	}
+	if db.UseDirectIOForFlushAndCompaction != nil {
+		opts.SetUseDirectIOForFlushAndCompaction(*db.UseDirectIOForFlushAndCompaction)
+	}
+	if db.EnablePipelinedWrite != nil {
+		opts.SetEnablePipelinedWrite(*db.EnablePipelinedWrite)
+	}
+	if db.UnorderedWrite != nil {
+		opts.SetUnorderedWrite(*db.UnorderedWrite)
+	}
+	if db.AtomicFlush != nil {
+		opts.SetAtomicFlush(*db.AtomicFlush)
+	}
+	if db.StatsDumpPeriodSec != nil {
+		opts.SetStatsDumpPeriodSec(*db.StatsDumpPeriodSec)
+	}
+}
+
+func (cf *ColumnFamilyConfig) apply(opts *Options) {
+	if cf.WriteBufferSize != nil {
+		opts.SetWriteBufferSize(*cf.WriteBufferSize)
+	}
+	if cf.MaxWriteBufferNumber != nil {
+		opts.SetMaxWriteBufferNumber(*cf.MaxWriteBufferNumber)
+	}
+	if cf.MinWriteBufferNumberToMerge != nil {
+		opts.SetMinWriteBufferNumberToMerge(*cf.MinWriteBufferNumberToMerge)
+	}
+	if cf.NumLevels != nil {
+		opts.SetNumLevels(*cf.NumLevels)
+	}
+	if cf.Level0FileNumCompactionTrigger != nil {
+		opts.SetLevel0FileNumCompactionTrigger(*cf.Level0FileNumCompactionTrigger)
+	}
+	if cf.Level0SlowdownWritesTrigger != nil {
+		opts.SetLevel0SlowdownWritesTrigger(*cf.Level0SlowdownWritesTrigger)
+	}
+	if cf.Level0StopWritesTrigger != nil {
+		opts.SetLevel0StopWritesTrigger(*cf.Level0StopWritesTrigger)
+	}
+	if cf.TargetFileSizeBase != nil {
+		opts.SetTargetFileSizeBase(*cf.TargetFileSizeBase)
+	}
+	if cf.TargetFileSizeMultiplier != nil {
+		opts.SetTargetFileSizeMultiplier(*cf.TargetFileSizeMultiplier)
+	}
+	if cf.MaxBytesForLevelBase != nil {
+		opts.SetMaxBytesForLevelBase(*cf.MaxBytesForLevelBase)
+	}
+	if cf.MaxBytesForLevelMultiplier != nil {
+		opts.SetMaxBytesForLevelMultiplier(*cf.MaxBytesForLevelMultiplier)
+	}
+	if cf.LevelCompactionDynamicLevelBytes != nil {
+		opts.SetLevelCompactionDynamicLevelBytes(*cf.LevelCompactionDynamicLevelBytes)
+	}
+	if cf.DisableAutoCompactions != nil {
+		opts.SetDisableAutoCompactions(*cf.DisableAutoCompactions)
+	}
+	if cf.OptimizeFiltersForHits != nil {
+		opts.SetOptimizeFiltersForHits(*cf.OptimizeFiltersForHits)
+	}
+	if cf.MaxSuccessiveMerges != nil {
+		opts.SetMaxSuccessiveMerges(*cf.MaxSuccessiveMerges)
+	}
+	if cf.MemtablePrefixBloomSizeRatio != nil {
+		opts.SetMemtablePrefixBloomSizeRatio(*cf.MemtablePrefixBloomSizeRatio)
+	}
+	if cf.FixedPrefixLength != nil {
+		opts.SetPrefixExtractor(NewFixedPrefixTransform(*cf.FixedPrefixLength))
+	}
+
+	if cf.Compression != "" {
+		opts.SetCompression(compressionTypesByName[cf.Compression])
+	}
+	if len(cf.CompressionPerLevel) > 0 {
+		levels := make([]CompressionType, len(cf.CompressionPerLevel))
+		for i, name := range cf.CompressionPerLevel {
+			levels[i] = compressionTypesByName[name]
+		}
+		opts.SetCompressionPerLevel(levels)
+	}
+	if cf.BottommostCompression != "" {
+		opts.SetBottommostCompression(compressionTypesByName[cf.BottommostCompression])
+	}
+	if cf.CompressionOptions != nil {
+		opts.SetCompressionOptions(cf.CompressionOptions.build())
+	}
+
+	if cf.CompactionStyle != "" {
+		opts.SetCompactionStyle(compactionStylesByName[cf.CompactionStyle])
+	}
+	if cf.Universal != nil {
+		// the options are copied, so they can be destroyed right away
+		uco := cf.Universal.build()
+		opts.SetUniversalCompactionOptions(uco)
+		uco.Destroy()
+	}
+	if cf.FIFO != nil {
+		fifo := NewDefaultFIFOCompactionOptions()
+		if cf.FIFO.MaxTableFilesSize != nil {
+			fifo.SetMaxTableFilesSize(*cf.FIFO.MaxTableFilesSize)
+		}
+		opts.SetFIFOCompactionOptions(fifo)
+		fifo.Destroy()
+	}
+
+	if cf.Table != nil {
+		bbto := cf.Table.build()
+		opts.SetBlockBasedTableFactory(bbto)
+		bbto.Destroy()
+		opts.bbto = nil
+	}
+}
+
+func (c *CompressionConfig) build() *CompressionOptions {
+	value := NewDefaultCompressionOptions()
+	if c.WindowBits != nil {
+		value.WindowBits = *c.WindowBits
+	}
+	if c.Level != nil {
+		value.Level = *c.Level
+	}
+	if c.Strategy != nil {
+		value.Strategy = *c.Strategy
+	}
+	if c.MaxDictBytes != nil {
+		value.MaxDictBytes = *c.MaxDictBytes
+	}
+	if c.ZstdMaxTrainBytes != nil {
+		value.ZstdMaxTrainBytes = *c.ZstdMaxTrainBytes
+	}
+	return value
+}
+
+func (c *UniversalCompactionConfig) build() *UniversalCompactionOptions {
+	uco := NewDefaultUniversalCompactionOptions()
+	if c.SizeRatio != nil {
+		uco.SetSizeRatio(*c.SizeRatio)
+	}
+	if c.MinMergeWidth != nil {
+		uco.SetMinMergeWidth(*c.MinMergeWidth)
+	}
+	if c.MaxMergeWidth != nil {
+		uco.SetMaxMergeWidth(*c.MaxMergeWidth)
+	}
+	if c.MaxSizeAmplificationPercent != nil {
+		uco.SetMaxSizeAmplificationPercent(*c.MaxSizeAmplificationPercent)
+	}
+	if c.CompressionSizePercent != nil {
+		uco.SetCompressionSizePercent(*c.CompressionSizePercent)
+	}
+	if c.StopStyle != "" {
+		uco.SetStopStyle(stopStylesByName[c.StopStyle])
+	}
+	return uco
+}
+
+// build creates the table options, which are copied by the table factory
+// along with the references to their block cache and filter policy.
+func (c *BlockBasedTableConfig) build() *BlockBasedTableOptions {
+	bbto := NewDefaultBlockBasedTableOptions()
+	if c.BlockSize != nil {
+		bbto.SetBlockSize(*c.BlockSize)
+	}
+	if c.BlockSizeDeviation != nil {
+		bbto.SetBlockSizeDeviation(*c.BlockSizeDeviation)
+	}
+	if c.BlockRestartInterval != nil {
+		bbto.SetBlockRestartInterval(*c.BlockRestartInterval)
+	}
+	if c.CacheIndexAndFilterBlocks != nil {
+		bbto.SetCacheIndexAndFilterBlocks(*c.CacheIndexAndFilterBlocks)
+	}
+	if c.PinL0FilterAndIndexBlocksInCache != nil {
+		bbto.SetPinL0FilterAndIndexBlocksInCache(*c.PinL0FilterAndIndexBlocksInCache)
+	}
+	if c.WholeKeyFiltering != nil {
+		bbto.SetWholeKeyFiltering(*c.WholeKeyFiltering)
+	}
+	if c.NoBlockCache != nil {
+		bbto.SetNoBlockCache(*c.NoBlockCache)
+	}
+	if c.BlockCacheSize != nil {
+		cache := NewLRUCache(*c.BlockCacheSize)
+		bbto.SetBlockCache(cache)
+		cache.Destroy()
+	}
+	if c.BloomFilterBitsPerKey != nil {
+		bbto.SetFilterPolicy(NewBloomFilterFull(*c.BloomFilterBitsPerKey))
+	}
+	if c.IndexType != "" {
+		bbto.SetIndexType(indexTypesByName[c.IndexType])
+	}
+	if c.DataBlockIndexType != "" {
+		bbto.SetDataBlockIndexType(dataBlockIndexTypesByName[c.DataBlockIndexType])
+	}
+	if c.DataBlockHashRatio != nil {
+		bbto.SetDataBlockHashRadio(*c.DataBlockHashRatio)
+	}
+	return bbto
+}
+
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
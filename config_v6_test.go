@@ -0,0 +1,93 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+const testConfig = `{
+	"db": {"create_if_missing": true, "create_missing_column_families": true, "max_open_files": 42},
+	"column_family": {
+		"write_buffer_size": 1048576,
+		"compression": "snappy",
+		"table": {"block_size": 8192, "block_cache_size": 1048576, "bloom_filter_bits_per_key": 10}
+	},
+	"column_families": {
+		"guide": {
+			"compaction_style": "universal",
+			"universal": {"size_ratio": 2, "stop_style": "total_size"},
+			"compression_per_level": ["none", "lz4", "zstd"]
+		}
+	}
+}`
+
+func TestConfigBuild(t *testing.T) {
+	var cfg Config
+	ensure.Nil(t, json.Unmarshal([]byte(testConfig), &cfg))
+	ensure.Nil(t, cfg.Validate())
+
+	opts, names, cfOpts, err := cfg.BuildColumnFamilies()
+	ensure.Nil(t, err)
+	defer opts.Destroy()
+	for _, o := range cfOpts {
+		defer o.Destroy()
+	}
+	ensure.DeepEqual(t, names, []string{"default", "guide"})
+	ensure.True(t, opts.GetCreateIfMissing())
+	ensure.DeepEqual(t, opts.GetMaxOpenFiles(), 42)
+	ensure.DeepEqual(t, opts.GetWriteBufferSize(), 1048576)
+	ensure.DeepEqual(t, cfOpts[0].GetCompactionStyle(), LevelCompactionStyle)
+	// the guide column family inherits the default column family options
+	ensure.DeepEqual(t, cfOpts[1].GetWriteBufferSize(), 1048576)
+	ensure.DeepEqual(t, cfOpts[1].GetCompression(), SnappyCompression)
+	ensure.DeepEqual(t, cfOpts[1].GetCompactionStyle(), UniversalCompactionStyle)
+	ensure.DeepEqual(t, cfOpts[1].GetCompressionPerLevel(), []CompressionType{NoCompression, LZ4Compression, ZSTDCompression})
+
+	dir, cleanup := newTestDBDir(t, "TestConfigBuild")
+	defer cleanup()
+	db, cfh, err := OpenDbColumnFamilies(opts, dir, names, cfOpts)
+	ensure.Nil(t, err)
+	for _, cf := range cfh {
+		cf.Destroy()
+	}
+	db.Close()
+}
+
+func TestConfigValidate(t *testing.T) {
+	var cfg Config
+	ensure.Nil(t, json.Unmarshal([]byte(`{
+		"db": {"use_direct_reads": true, "allow_mmap_reads": true},
+		"column_family": {
+			"compression": "gzip",
+			"level0_slowdown_writes_trigger": 20,
+			"level0_stop_writes_trigger": 10,
+			"table": {"data_block_hash_ratio": 0.75}
+		},
+		"column_families": {"guide": {"fifo": {"max_table_files_size": 1024}}}
+	}`), &cfg))
+
+	err := cfg.Validate()
+	ensure.NotNil(t, err)
+	var fields []string
+	for _, e := range err.(ConfigErrors) {
+		fields = append(fields, e.Field)
+	}
+	ensure.DeepEqual(t, fields, []string{
+		"db.use_direct_reads",
+		"column_family.level0_stop_writes_trigger",
+		"column_family.compression",
+		"column_family.table.data_block_hash_ratio",
+		"column_families.guide.level0_stop_writes_trigger",
+		"column_families.guide.compression",
+		"column_families.guide.fifo",
+		"column_families.guide.table.data_block_hash_ratio",
+	})
+
+	_, err = cfg.Build()
+	ensure.NotNil(t, err)
+}
@@ -1,12 +1,18 @@
 package gorocksdb
 
+// #cgo CXXFLAGS: -std=c++17
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+// #include "gorocksdb.h"
 import "C"
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -23,6 +29,14 @@ type DB struct {
 	closer func(*C.rocksdb_t)
 	name   string
 	opts   *Options
+
+	snapshots snapshotTracker
+	cfs       ColumnFamilies
+	merges    *mergeFailureLogs
+
+	// manualCompactionsDisabled counts the DisableManualCompaction calls
+	// not undone by EnableManualCompaction, like RocksDB does.
+	manualCompactionsDisabled int32
 }
 
 func dbClose(c *C.rocksdb_t) {
@@ -46,6 +60,7 @@ func OpenDb(opts *Options, name string) (*DB, error) {
 		closer: dbClose,
 		name:   name,
 		opts:   opts,
+		merges: newMergeFailureLogs(opts),
 	}, nil
 }
 
@@ -66,6 +81,7 @@ func OpenDbWithTTL(opts *Options, name string, ttl int) (*DB, error) {
 		closer: dbClose,
 		name:   name,
 		opts:   opts,
+		merges: newMergeFailureLogs(opts),
 	}, nil
 }
 
@@ -86,6 +102,7 @@ func OpenDbForReadOnly(opts *Options, name string, errorIfLogFileExist bool) (*D
 		closer: dbClose,
 		name:   name,
 		opts:   opts,
+		merges: newMergeFailureLogs(opts),
 	}, nil
 }
 
@@ -146,6 +163,7 @@ func OpenDbColumnFamilies(
 		closer: dbClose,
 		name:   name,
 		opts:   opts,
+		merges: newMergeFailureLogs(append([]*Options{opts}, cfOpts...)...),
 	}, cfHandles, nil
 }
 
@@ -209,6 +227,7 @@ func OpenDbForReadOnlyColumnFamilies(
 		closer: dbClose,
 		name:   name,
 		opts:   opts,
+		merges: newMergeFailureLogs(append([]*Options{opts}, cfOpts...)...),
 	}, cfHandles, nil
 }
 
@@ -256,7 +275,7 @@ func (db *DB) Get(opts *ReadOptions, key []byte) (*Slice, error) {
 	runtime.KeepAlive(key)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, db.mergeAwareError(cErr, key)
 	}
 	return NewSlice(cValue, cValLen), nil
 }
@@ -272,7 +291,7 @@ func (db *DB) GetBytes(opts *ReadOptions, key []byte) ([]byte, error) {
 	runtime.KeepAlive(key)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, db.mergeAwareError(cErr, key)
 	}
 	if cValue == nil {
 		return nil, nil
@@ -292,11 +311,31 @@ func (db *DB) GetCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (*Sli
 	runtime.KeepAlive(key)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, db.mergeAwareError(cErr, key)
 	}
 	return NewSlice(cValue, cValLen), nil
 }
 
+// GetBytesCF is like GetCF but returns a copy of the data.
+func (db *DB) GetBytesCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) ([]byte, error) {
+	var (
+		cErr    *C.char
+		cValLen C.size_t
+		cKey    = byteToChar(key)
+	)
+	cValue := C.rocksdb_get_cf(db.c, opts.c, cf.c, cKey, C.size_t(len(key)), &cValLen, &cErr)
+	runtime.KeepAlive(key)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, db.mergeAwareError(cErr, key)
+	}
+	if cValue == nil {
+		return nil, nil
+	}
+	defer C.free(unsafe.Pointer(cValue))
+	return C.GoBytes(unsafe.Pointer(cValue), C.int(cValLen)), nil
+}
+
 // GetPinned returns the data associated with the key from the database.
 func (db *DB) GetPinned(opts *ReadOptions, key []byte) (*PinnableSliceHandle, error) {
 	var (
@@ -307,7 +346,7 @@ func (db *DB) GetPinned(opts *ReadOptions, key []byte) (*PinnableSliceHandle, er
 	runtime.KeepAlive(key)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return nil, errors.New(C.GoString(cErr))
+		return nil, db.mergeAwareError(cErr, key)
 	}
 	return NewNativePinnableSliceHandle(cHandle), nil
 }
@@ -353,6 +392,71 @@ func (db *DB) MultiGet(opts *ReadOptions, keys ...[]byte) (Slices, error) {
 	return slices, nil
 }
 
+// MultiGetPinned returns the data associated with the passed keys from the
+// database as pinned slices, avoiding a copy of each value. A key that is not
+// found yields a handle with nil Data. The handles must be destroyed.
+//
+// It is not RocksDB's batched MultiGet: the keys are looked up one at a
+// time, by a single cgo call, which saves the overhead of a call per key but
+// doesn't batch the reads.
+func (db *DB) MultiGetPinned(opts *ReadOptions, keys ...[]byte) (PinnableSliceHandles, error) {
+	return db.multiGetPinned(opts, nil, keys)
+}
+
+// MultiGetPinnedCF returns the data associated with the passed keys from the
+// column family as pinned slices, looking them up one at a time like
+// MultiGetPinned. The handles must be destroyed.
+func (db *DB) MultiGetPinnedCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys ...[]byte) (PinnableSliceHandles, error) {
+	return db.multiGetPinned(opts, cf.c, keys)
+}
+
+func (db *DB) multiGetPinned(opts *ReadOptions, cf *C.rocksdb_column_family_handle_t, keys [][]byte) (PinnableSliceHandles, error) {
+	if len(keys) == 0 {
+		return PinnableSliceHandles{}, nil
+	}
+
+	cKeys, cKeySizes := byteSlicesToCSlices(keys)
+	defer cKeys.Destroy()
+	vals := make([]*C.rocksdb_pinnableslice_t, len(keys))
+	rocksErrs := make(charsSlice, len(keys))
+
+	C.gorocksdb_multi_get_pinned(
+		db.c,
+		opts.c,
+		cf,
+		C.size_t(len(keys)),
+		cKeys.c(),
+		cKeySizes.c(),
+		&vals[0],
+		rocksErrs.c(),
+	)
+
+	handles := newPinnableSliceHandles(vals)
+	if err := multiGetError(keys, rocksErrs); err != nil {
+		handles.Destroy()
+		return nil, err
+	}
+	return handles, nil
+}
+
+// multiGetError frees the errors of a batched get, aggregating them into one.
+func multiGetError(keys [][]byte, rocksErrs charsSlice) error {
+	var errs []error
+
+	for i, rocksErr := range rocksErrs {
+		if rocksErr != nil {
+			defer C.free(unsafe.Pointer(rocksErr))
+			err := fmt.Errorf("getting %q failed: %v", string(keys[i]), C.GoString(rocksErr))
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to get %d keys, first error: %v", len(errs), errs[0])
+	}
+	return nil
+}
+
 // MultiGetCF returns the data associated with the passed keys from the column family
 func (db *DB) MultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys ...[]byte) (Slices, error) {
 	cfs := make(ColumnFamilyHandles, len(keys))
@@ -481,7 +585,7 @@ func (db *DB) Merge(opts *WriteOptions, key []byte, value []byte) error {
 	runtime.KeepAlive(value)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return db.mergeAwareError(cErr, key)
 	}
 	return nil
 }
@@ -499,11 +603,32 @@ func (db *DB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, va
 	runtime.KeepAlive(value)
 	if cErr != nil {
 		defer C.free(unsafe.Pointer(cErr))
-		return errors.New(C.GoString(cErr))
+		return db.mergeAwareError(cErr, key)
 	}
 	return nil
 }
 
+// Increment adds delta to the counter stored at the key, by merging it
+// encoded on 8 bytes in little-endian order. The column family must use a
+// merge operator adding such integers, such as mergeops.Int64Add or
+// mergeops.Uint64Add with the fixed encoding; negative deltas decrement
+// the counter.
+func (db *DB) Increment(opts *WriteOptions, key []byte, delta int64) error {
+	return db.Merge(opts, key, encodeCounterDelta(delta))
+}
+
+// IncrementCF adds delta to the counter stored at the key in the column
+// family. See Increment.
+func (db *DB) IncrementCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, delta int64) error {
+	return db.MergeCF(opts, cf, key, encodeCounterDelta(delta))
+}
+
+func encodeCounterDelta(delta int64) []byte {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(delta))
+	return buf
+}
+
 // Write writes a WriteBatch to the database
 func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
 	var cErr *C.char
@@ -518,25 +643,82 @@ func (db *DB) Write(opts *WriteOptions, batch *WriteBatch) error {
 // NewIterator returns an Iterator over the the database that uses the
 // ReadOptions given.
 func (db *DB) NewIterator(opts *ReadOptions) *Iterator {
-	cIter := C.rocksdb_create_iterator(db.c, opts.c)
-	return NewNativeIterator(unsafe.Pointer(cIter))
+	return db.newIterator(opts, false, nil)
 }
 
 // NewIteratorCF returns an Iterator over the the database and column family
 // that uses the ReadOptions given.
 func (db *DB) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
-	cIter := C.rocksdb_create_iterator_cf(db.c, opts.c, cf.c)
-	return NewNativeIterator(unsafe.Pointer(cIter))
+	return db.newIterator(opts, false, cf)
+}
+
+// NewIterators returns an Iterator per column family, all reading from the
+// same consistent view of the database. Refreshing them would break that
+// view, so their Refresh method fails with a Not supported error.
+func (db *DB) NewIterators(opts *ReadOptions, cfs ColumnFamilyHandles) ([]*Iterator, error) {
+	if len(cfs) == 0 {
+		return []*Iterator{}, nil
+	}
+
+	var cErr *C.char
+	cIters := make([]*C.rocksdb_iterator_t, len(cfs))
+	C.rocksdb_create_iterators(db.c, opts.c, cfs.toCSlice().c(), &cIters[0], C.size_t(len(cfs)), &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+
+	iters := make([]*Iterator, len(cfs))
+	for i, c := range cIters {
+		iters[i] = NewNativeIterator(unsafe.Pointer(c))
+		iters[i].pinned = opts.pinData
+	}
+	return iters, nil
+}
+
+// NewIteratorWithBounds returns an Iterator over the database limited to the
+// keys in [lower, upper), using a copy of the ReadOptions given. The bounds
+// are owned by the iterator, so the ReadOptions can be changed, reused or
+// destroyed once the iterator is created. A nil bound leaves that side open.
+func (db *DB) NewIteratorWithBounds(opts *ReadOptions, lower, upper []byte) *Iterator {
+	return db.newIterator(boundedReadOptions(opts, lower, upper), true, nil)
+}
+
+// NewIteratorCFWithBounds returns an Iterator over the column family limited
+// to the keys in [lower, upper), like NewIteratorWithBounds.
+func (db *DB) NewIteratorCFWithBounds(opts *ReadOptions, cf *ColumnFamilyHandle, lower, upper []byte) *Iterator {
+	return db.newIterator(boundedReadOptions(opts, lower, upper), true, cf)
+}
+
+func boundedReadOptions(opts *ReadOptions, lower, upper []byte) *ReadOptions {
+	opts = opts.Clone()
+	opts.SetIterateLowerBound(lower)
+	opts.SetIterateUpperBound(upper)
+	return opts
+}
+
+func (db *DB) newIterator(opts *ReadOptions, ownsOpts bool, cf *ColumnFamilyHandle) *Iterator {
+	if cf == nil {
+		return newIterator(opts, ownsOpts, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+			return C.rocksdb_create_iterator(db.c, opts)
+		})
+	}
+	return newIterator(opts, ownsOpts, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.rocksdb_create_iterator_cf(db.c, opts, cf.c)
+	})
 }
 
 // NewSnapshot creates a new snapshot of the database.
 func (db *DB) NewSnapshot() *Snapshot {
 	cSnap := C.rocksdb_create_snapshot(db.c)
+	db.snapshots.add(cSnap)
 	return NewNativeSnapshot(cSnap)
 }
 
 // ReleaseSnapshot releases the snapshot and its resources.
 func (db *DB) ReleaseSnapshot(snapshot *Snapshot) {
+	trackFree(snapshot)
+	db.snapshots.remove(snapshot.c)
 	C.rocksdb_release_snapshot(db.c, snapshot.c)
 	snapshot.c = nil
 }
@@ -571,6 +753,7 @@ func (db *DB) CreateColumnFamily(opts *Options, name string) (*ColumnFamilyHandl
 		defer C.free(unsafe.Pointer(cErr))
 		return nil, errors.New(C.GoString(cErr))
 	}
+	db.merges.add(opts)
 	return NewNativeColumnFamilyHandle(cHandle), nil
 }
 
@@ -618,15 +801,20 @@ func (db *DB) SetOptions(keys, values []string) error {
 
 // LiveFileMetadata is a metadata which is associated with each SST file.
 type LiveFileMetadata struct {
-	Name        string
-	Level       int
-	Size        int64
-	SmallestKey []byte
-	LargestKey  []byte
+	Name string
+	// ColumnFamilyName is empty with RocksDB 5, whose C API doesn't report
+	// it.
+	ColumnFamilyName string
+	Level            int
+	Size             int64
+	SmallestKey      []byte
+	LargestKey       []byte
+	NumEntries       uint64
+	NumDeletions     uint64
 }
 
 // GetLiveFilesMetaData returns a list of all table files with their
-// level, start key and end key.
+// column family, level, start key, end key and number of entries.
 func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
 	lf := C.rocksdb_livefiles(db.c)
 	defer C.rocksdb_livefiles_destroy(lf)
@@ -636,8 +824,11 @@ func (db *DB) GetLiveFilesMetaData() []LiveFileMetadata {
 	for i := C.int(0); i < count; i++ {
 		var liveFile LiveFileMetadata
 		liveFile.Name = C.GoString(C.rocksdb_livefiles_name(lf, i))
+		liveFile.ColumnFamilyName = liveFileColumnFamilyName(lf, i)
 		liveFile.Level = int(C.rocksdb_livefiles_level(lf, i))
 		liveFile.Size = int64(C.rocksdb_livefiles_size(lf, i))
+		liveFile.NumEntries = uint64(C.rocksdb_livefiles_entries(lf, i))
+		liveFile.NumDeletions = uint64(C.rocksdb_livefiles_deletions(lf, i))
 
 		var cSize C.size_t
 		key := C.rocksdb_livefiles_smallestkey(lf, i, &cSize)
@@ -668,6 +859,142 @@ func (db *DB) CompactRangeCF(cf *ColumnFamilyHandle, r Range) {
 	runtime.KeepAlive(r)
 }
 
+// ErrManualCompactionDisabled is returned by CompactRangeOpt and
+// CompactRangeCFOpt when manual compactions are disabled by
+// DisableManualCompaction, in which case the range may not be compacted.
+var ErrManualCompactionDisabled = errors.New("Incomplete: Manual compaction paused")
+
+// CompactRangeOpt runs a manual compaction on the Range of keys given, with
+// the given options. See CompactRangeCFOpt.
+func (db *DB) CompactRangeOpt(r Range, opts *CompactRangeOptions) error {
+	return db.compactRangeOpt(nil, r, opts)
+}
+
+// CompactRangeCFOpt runs a manual compaction on the Range of keys given on
+// the given column family, with the given options.
+//
+// The RocksDB C API doesn't return the status of manual compactions, so the
+// only error returned is ErrManualCompactionDisabled, when manual compactions
+// were disabled by DisableManualCompaction before or while it ran. Other
+// failures, such as invalid options or I/O errors, are not reported.
+//
+// Whether it was disabled while it ran is told by checking again once the
+// compaction returns, so ErrManualCompactionDisabled is also returned when
+// DisableManualCompaction is called after the compaction completed but
+// before it returned; the range was then compacted.
+func (db *DB) CompactRangeCFOpt(cf *ColumnFamilyHandle, r Range, opts *CompactRangeOptions) error {
+	return db.compactRangeOpt(cf, r, opts)
+}
+
+func (db *DB) compactRangeOpt(cf *ColumnFamilyHandle, r Range, opts *CompactRangeOptions) error {
+	if atomic.LoadInt32(&db.manualCompactionsDisabled) > 0 {
+		return ErrManualCompactionDisabled
+	}
+	cStart := byteToChar(r.Start)
+	cLimit := byteToChar(r.Limit)
+	if cf == nil {
+		C.rocksdb_compact_range_opt(db.c, opts.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
+	} else {
+		C.rocksdb_compact_range_cf_opt(db.c, cf.c, opts.c, cStart, C.size_t(len(r.Start)), cLimit, C.size_t(len(r.Limit)))
+	}
+	runtime.KeepAlive(r)
+	if atomic.LoadInt32(&db.manualCompactionsDisabled) > 0 {
+		return ErrManualCompactionDisabled
+	}
+	return nil
+}
+
+// backgroundErrors returns the number of background errors so far, as
+// reported by the "rocksdb.background-errors" property.
+func (db *DB) backgroundErrors() (uint64, error) {
+	return strconv.ParseUint(db.GetProperty("rocksdb.background-errors"), 10, 64)
+}
+
+// PauseBackgroundWork stops the background work, flushes and compactions,
+// waiting for the running jobs to finish, until ContinueBackgroundWork is
+// called as many times. The writes stall once the memtables are full, since
+// they are not flushed meanwhile.
+func (db *DB) PauseBackgroundWork() error {
+	var cErr *C.char
+	C.gorocksdb_pause_background_work(db.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// ContinueBackgroundWork resumes the background work paused by
+// PauseBackgroundWork.
+func (db *DB) ContinueBackgroundWork() error {
+	var cErr *C.char
+	C.gorocksdb_continue_background_work(db.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// waitForCompactInterval is the interval at which WaitForCompact polls the
+// database.
+const waitForCompactInterval = 10 * time.Millisecond
+
+// ErrWaitForCompactTimeout is returned by WaitForCompact when the background
+// work is not done before the timeout.
+var ErrWaitForCompactTimeout = errors.New("Operation timed out: background work still pending")
+
+// WaitForCompact waits until no flush or compaction is running or pending
+// in the default column family, or the timeout expires, a timeout <= 0
+// meaning no timeout. It polls the database properties, so it doesn't
+// prevent new work from being scheduled meanwhile.
+func (db *DB) WaitForCompact(timeout time.Duration) error {
+	return db.WaitForCompactCF(timeout)
+}
+
+// WaitForCompactCF waits like WaitForCompact until no flush or compaction is
+// running or pending in the default column family and the given ones.
+func (db *DB) WaitForCompactCF(timeout time.Duration, cfs ...*ColumnFamilyHandle) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	for {
+		idle, err := db.backgroundIdle(cfs)
+		if err != nil || idle {
+			return err
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return ErrWaitForCompactTimeout
+		}
+		time.Sleep(waitForCompactInterval)
+	}
+}
+
+// backgroundIdle reports whether no flush or compaction is running or
+// pending in the default column family and the given ones.
+func (db *DB) backgroundIdle(cfs []*ColumnFamilyHandle) (bool, error) {
+	for _, prop := range []string{"rocksdb.num-running-flushes", "rocksdb.num-running-compactions"} {
+		if n, err := strconv.ParseUint(db.GetProperty(prop), 10, 64); err != nil || n > 0 {
+			return false, err
+		}
+	}
+	for _, cf := range append([]*ColumnFamilyHandle{nil}, cfs...) {
+		for _, prop := range []string{"rocksdb.mem-table-flush-pending", "rocksdb.compaction-pending"} {
+			value := ""
+			if cf == nil {
+				value = db.GetProperty(prop)
+			} else {
+				value = db.GetPropertyCF(prop, cf)
+			}
+			if n, err := strconv.ParseUint(value, 10, 64); err != nil || n > 0 {
+				return false, err
+			}
+		}
+	}
+	return true, nil
+}
+
 // Flush triggers a manuel flush for the database.
 func (db *DB) Flush(opts *FlushOptions) error {
 	var cErr *C.char
@@ -833,6 +1160,7 @@ func (db *DB) NewCheckpoint() (*Checkpoint, error) {
 
 // Close closes the database.
 func (db *DB) Close() {
+	db.cfs.destroy()
 	db.closer(db.c)
 }
 
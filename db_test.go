@@ -2,6 +2,7 @@ package gorocksdb
 
 import (
 	"io/ioutil"
+	"os"
 	"strconv"
 	"testing"
 
@@ -123,7 +124,7 @@ func TestDBCRUDDBPaths(t *testing.T) {
 	ensure.DeepEqual(t, v4.Data(), []byte(nil))
 }
 
-func newTestDB(t *testing.T, name string, applyOpts func(opts *Options)) *DB {
+func newTestDB(t testing.TB, name string, applyOpts func(opts *Options)) *DB {
 	dir, err := ioutil.TempDir("", "gorocksdb-"+name)
 	ensure.Nil(t, err)
 
@@ -141,6 +142,14 @@ func newTestDB(t *testing.T, name string, applyOpts func(opts *Options)) *DB {
 	return db
 }
 
+// newTestDBDir creates a temporary directory for a database, removed by
+// cleanup.
+func newTestDBDir(t testing.TB, name string) (dir string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "gorocksdb-"+name)
+	ensure.Nil(t, err)
+	return dir, func() { os.RemoveAll(dir) }
+}
+
 func newTestDBPathNames(t *testing.T, name string, names []string, target_sizes []uint64, applyOpts func(opts *Options)) *DB {
 	ensure.DeepEqual(t, len(target_sizes), len(names))
 	ensure.NotDeepEqual(t, len(names), 0)
@@ -204,3 +213,54 @@ func TestDBMultiGet(t *testing.T) {
 	ensure.DeepEqual(t, values[2].Data(), givenVal2)
 	ensure.DeepEqual(t, values[3].Data(), givenVal3)
 }
+
+func TestDBMultiGetPinned(t *testing.T) {
+	db := newTestDB(t, "TestDBMultiGetPinned", nil)
+	defer db.Close()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+	)
+
+	// create
+	ensure.Nil(t, db.Put(wo, givenKey1, givenVal1))
+	ensure.Nil(t, db.Put(wo, givenKey2, givenVal2))
+
+	// retrieve
+	values, err := db.MultiGetPinned(ro, []byte("noexist"), givenKey1, givenKey2)
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(values), 3)
+
+	ensure.DeepEqual(t, values[0].Data(), []byte(nil))
+	ensure.DeepEqual(t, values[1].Data(), givenVal1)
+	ensure.DeepEqual(t, values[2].Data(), givenVal2)
+
+}
+
+func TestDBCompactRangeOpt(t *testing.T) {
+	db := newTestDB(t, "TestDBCompactRangeOpt", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 10; i++ {
+		ensure.Nil(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("val")))
+	}
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "1")
+
+	opts := NewDefaultCompactRangeOptions()
+	defer opts.Destroy()
+	opts.SetExclusiveManualCompaction(true)
+	opts.SetBottommostLevelCompaction(BottommostLevelCompactionForce)
+	opts.SetChangeLevel(true)
+	opts.SetTargetLevel(2)
+	ensure.Nil(t, db.CompactRangeOpt(Range{nil, nil}, opts))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level2"), "1")
+}
@@ -0,0 +1,19 @@
+//go:build !v6
+// +build !v6
+
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// liveFileColumnFamilyName returns "": the RocksDB 5 C API doesn't report the
+// column family of the live files.
+func liveFileColumnFamilyName(lf *C.rocksdb_livefiles_t, i C.int) string {
+	return ""
+}
+
+// approximateSizes returns no sizes: the approximate sizes are not read with
+// RocksDB 5.
+func (db *DB) approximateSizes(cf *ColumnFamilyHandle, ranges []Range) ([]uint64, error) {
+	return nil, nil
+}
@@ -5,9 +5,30 @@ package gorocksdb
 
 // #include <stdlib.h>
 // #include "rocksdb/c.h"
+//
+// static void gorocksdb_batched_multi_get_cf(rocksdb_t* db, const rocksdb_readoptions_t* options,
+//     rocksdb_column_family_handle_t* cf, size_t num_keys, const char* const* keys_list,
+//     const size_t* keys_list_sizes, rocksdb_pinnableslice_t** values, char** errs, unsigned char sorted_input) {
+//     rocksdb_batched_multi_get_cf(db, options, cf, num_keys, keys_list, keys_list_sizes, values, errs, sorted_input);
+// }
+//
+// static void gorocksdb_keys_may_exist_cf(rocksdb_t* db, const rocksdb_readoptions_t* options,
+//     rocksdb_column_family_handle_t* cf, size_t num_keys, char** keys, size_t* key_sizes,
+//     unsigned char* results) {
+//     size_t i;
+//     for (i = 0; i < num_keys; i++) {
+//         if (cf == NULL) {
+//             results[i] = rocksdb_key_may_exist(db, options, keys[i], key_sizes[i], NULL, NULL, NULL, 0, NULL);
+//         } else {
+//             results[i] = rocksdb_key_may_exist_cf(db, options, cf, keys[i], key_sizes[i], NULL, NULL, NULL, 0, NULL);
+//         }
+//     }
+// }
 import "C"
 import (
 	"errors"
+	"runtime"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -107,3 +128,230 @@ func (db *DB) GetApproximateSizesCF(cf *ColumnFamilyHandle, ranges []Range) (siz
 
 	return
 }
+
+// BatchedMultiGetCF returns the data associated with the passed keys from the
+// column family as pinned slices, looking them up in one batch. This is
+// faster than MultiGetCF for large batches since RocksDB can share index and
+// filter lookups between keys of the same block. If sortedInput is true, the
+// keys must already be sorted in the column family comparator order.
+// A key that is not found yields a handle with nil Data. The handles must be
+// destroyed.
+func (db *DB) BatchedMultiGetCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys [][]byte, sortedInput bool) (PinnableSliceHandles, error) {
+	if len(keys) == 0 {
+		return PinnableSliceHandles{}, nil
+	}
+
+	cKeys, cKeySizes := byteSlicesToCSlices(keys)
+	defer cKeys.Destroy()
+	vals := make([]*C.rocksdb_pinnableslice_t, len(keys))
+	rocksErrs := make(charsSlice, len(keys))
+
+	C.gorocksdb_batched_multi_get_cf(
+		db.c,
+		opts.c,
+		cf.c,
+		C.size_t(len(keys)),
+		cKeys.c(),
+		cKeySizes.c(),
+		&vals[0],
+		rocksErrs.c(),
+		boolToChar(sortedInput),
+	)
+
+	handles := newPinnableSliceHandles(vals)
+	if err := multiGetError(keys, rocksErrs); err != nil {
+		handles.Destroy()
+		return nil, err
+	}
+	return handles, nil
+}
+
+// KeyMayExist checks whether the key may exist in the database without
+// reading it from disk: only the memtables and the blocks already in the
+// block cache are consulted, so false means the key certainly doesn't exist
+// while true may be a false positive. When the value happens to be in memory
+// it is returned too, otherwise value is nil.
+//
+// RocksDB always reads at the BlockCacheTier here, whatever the read tier of
+// opts, so a filter block missing from the block cache is not read from disk
+// and the key is then reported as possibly existing.
+func (db *DB) KeyMayExist(opts *ReadOptions, key []byte) (mayExist bool, value []byte) {
+	return db.keyMayExist(opts, nil, key)
+}
+
+// KeyMayExistCF checks whether the key may exist in the column family,
+// like KeyMayExist.
+func (db *DB) KeyMayExistCF(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (mayExist bool, value []byte) {
+	return db.keyMayExist(opts, cf.c, key)
+}
+
+func (db *DB) keyMayExist(opts *ReadOptions, cf *C.rocksdb_column_family_handle_t, key []byte) (bool, []byte) {
+	var (
+		cValue      *C.char
+		cValLen     C.size_t
+		cValueFound C.uchar
+		cKey        = byteToChar(key)
+		cMayExist   C.uchar
+	)
+	if cf == nil {
+		cMayExist = C.rocksdb_key_may_exist(db.c, opts.c, cKey, C.size_t(len(key)),
+			&cValue, &cValLen, nil, 0, &cValueFound)
+	} else {
+		cMayExist = C.rocksdb_key_may_exist_cf(db.c, opts.c, cf, cKey, C.size_t(len(key)),
+			&cValue, &cValLen, nil, 0, &cValueFound)
+	}
+	runtime.KeepAlive(key)
+	if cValueFound == 0 || cValue == nil {
+		return cMayExist != 0, nil
+	}
+	defer C.free(unsafe.Pointer(cValue))
+	return cMayExist != 0, C.GoBytes(unsafe.Pointer(cValue), C.int(cValLen))
+}
+
+// KeysMayExist checks whether each of the keys may exist in the database,
+// like KeyMayExist but in a single call and without returning values.
+func (db *DB) KeysMayExist(opts *ReadOptions, keys [][]byte) []bool {
+	return db.keysMayExist(opts, nil, keys)
+}
+
+// KeysMayExistCF checks whether each of the keys may exist in the column
+// family, like KeysMayExist.
+func (db *DB) KeysMayExistCF(opts *ReadOptions, cf *ColumnFamilyHandle, keys [][]byte) []bool {
+	return db.keysMayExist(opts, cf.c, keys)
+}
+
+func (db *DB) keysMayExist(opts *ReadOptions, cf *C.rocksdb_column_family_handle_t, keys [][]byte) []bool {
+	mayExist := make([]bool, len(keys))
+	if len(keys) == 0 {
+		return mayExist
+	}
+
+	cKeys, cKeySizes := byteSlicesToCSlices(keys)
+	defer cKeys.Destroy()
+	cResults := make([]C.uchar, len(keys))
+
+	C.gorocksdb_keys_may_exist_cf(db.c, opts.c, cf, C.size_t(len(keys)), cKeys.c(), cKeySizes.c(), &cResults[0])
+
+	for i, r := range cResults {
+		mayExist[i] = r != 0
+	}
+	return mayExist
+}
+
+// CancelAllBackgroundWork requests all the background work, flushes and
+// compactions, to stop, and optionally waits for the running jobs to finish.
+// It can't be undone: no background work is scheduled afterwards, so the
+// database should be closed soon after.
+//
+// It is not a way to pause the background work, see PauseBackgroundWork.
+func (db *DB) CancelAllBackgroundWork(wait bool) {
+	C.rocksdb_cancel_all_background_work(db.c, boolToChar(wait))
+}
+
+// DisableManualCompaction cancels the running manual compactions, and makes
+// the following ones return immediately until EnableManualCompaction is
+// called as many times. CompactRange and CompactRangeCF then return without
+// compacting, and CompactRangeOpt and CompactRangeCFOpt return
+// ErrManualCompactionDisabled.
+func (db *DB) DisableManualCompaction() {
+	atomic.AddInt32(&db.manualCompactionsDisabled, 1)
+	C.rocksdb_disable_manual_compaction(db.c)
+}
+
+// EnableManualCompaction allows manual compactions again after
+// DisableManualCompaction. The calls without a matching
+// DisableManualCompaction are ignored, as RocksDB requires.
+func (db *DB) EnableManualCompaction() {
+	for {
+		n := atomic.LoadInt32(&db.manualCompactionsDisabled)
+		if n == 0 {
+			return
+		}
+		if atomic.CompareAndSwapInt32(&db.manualCompactionsDisabled, n, n-1) {
+			break
+		}
+	}
+	C.rocksdb_enable_manual_compaction(db.c)
+}
+
+// DisableAutoCompaction disables the automatic compactions of the column
+// families, nil or no column family meaning the default column family,
+// through the "disable_auto_compactions" option.
+func (db *DB) DisableAutoCompaction(cfs ...*ColumnFamilyHandle) error {
+	return db.setAutoCompaction(cfs, false)
+}
+
+// EnableAutoCompaction enables the automatic compactions of the column
+// families, nil or no column family meaning the default column family, and
+// schedules the compactions they need.
+func (db *DB) EnableAutoCompaction(cfs ...*ColumnFamilyHandle) error {
+	return db.setAutoCompaction(cfs, true)
+}
+
+func (db *DB) setAutoCompaction(cfs []*ColumnFamilyHandle, enable bool) error {
+	value := "true"
+	if enable {
+		value = "false"
+	}
+	if len(cfs) == 0 {
+		cfs = []*ColumnFamilyHandle{nil}
+	}
+	for _, cf := range cfs {
+		if err := db.SetOptionsCF(cf, []string{"disable_auto_compactions"}, []string{value}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetOptionsCF dynamically changes options of the column family, or of the
+// default column family if cf is nil. The keys are the names of the mutable
+// column family options, as found in the OPTIONS files; see
+// SetMutableCFOptions for a typed alternative. The database options can't be
+// changed: the C API doesn't expose DB::SetDBOptions.
+func (db *DB) SetOptionsCF(cf *ColumnFamilyHandle, keys, values []string) error {
+	if len(keys) != len(values) {
+		return errors.New("must provide the same number of option names and values")
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	cKeys := make(charsSlice, len(keys))
+	cValues := make(charsSlice, len(values))
+	for i := range keys {
+		cKeys[i] = C.CString(keys[i])
+		cValues[i] = C.CString(values[i])
+	}
+	defer func() {
+		for i := range keys {
+			C.free(unsafe.Pointer(cKeys[i]))
+			C.free(unsafe.Pointer(cValues[i]))
+		}
+	}()
+
+	var cErr *C.char
+	if cf == nil {
+		C.rocksdb_set_options(db.c, C.int(len(keys)), cKeys.c(), cValues.c(), &cErr)
+	} else {
+		C.rocksdb_set_options_cf(db.c, cf.c, C.int(len(keys)), cKeys.c(), cValues.c(), &cErr)
+	}
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// liveFileColumnFamilyName returns the column family of the i-th live file.
+func liveFileColumnFamilyName(lf *C.rocksdb_livefiles_t, i C.int) string {
+	return C.GoString(C.rocksdb_livefiles_column_family_name(lf, i))
+}
+
+// approximateSizes returns the approximate sizes of the ranges of the column
+// family, or of the default column family if cf is nil.
+func (db *DB) approximateSizes(cf *ColumnFamilyHandle, ranges []Range) ([]uint64, error) {
+	if cf == nil {
+		return db.GetApproximateSizes(ranges)
+	}
+	return db.GetApproximateSizesCF(cf, ranges)
+}
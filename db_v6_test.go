@@ -4,7 +4,9 @@
 package gorocksdb
 
 import (
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/facebookgo/ensure"
 )
@@ -53,3 +55,207 @@ func TestDBGetApproximateSizesCF(t *testing.T) {
 	ensure.Nil(t, err)
 	ensure.DeepEqual(t, sizes, []uint64{0})
 }
+
+func TestDBBatchedMultiGetCF(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBBatchedMultiGetCF")
+	defer cleanup()
+
+	var (
+		givenKey1 = []byte("hello1")
+		givenKey2 = []byte("hello2")
+		givenVal1 = []byte("world1")
+		givenVal2 = []byte("world2")
+		wo        = NewDefaultWriteOptions()
+		ro        = NewDefaultReadOptions()
+	)
+
+	// create
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey1, givenVal1))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey2, givenVal2))
+
+	// sorted input
+	values, err := db.BatchedMultiGetCF(ro, cfh[1], [][]byte{givenKey1, givenKey2, []byte("noexist")}, true)
+	defer values.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(values), 3)
+	ensure.DeepEqual(t, values[0].Data(), givenVal1)
+	ensure.DeepEqual(t, values[1].Data(), givenVal2)
+	ensure.DeepEqual(t, values[2].Data(), []byte(nil))
+
+	// unsorted input
+	values2, err := db.BatchedMultiGetCF(ro, cfh[1], [][]byte{givenKey2, givenKey1}, false)
+	defer values2.Destroy()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, values2[0].Data(), givenVal2)
+	ensure.DeepEqual(t, values2[1].Data(), givenVal1)
+}
+
+func TestDBKeyMayExist(t *testing.T) {
+	db := newTestDB(t, "TestDBKeyMayExist", nil)
+	defer db.Close()
+
+	var (
+		givenKey = []byte("hello")
+		givenVal = []byte("world")
+		wo       = NewDefaultWriteOptions()
+		ro       = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.Put(wo, givenKey, givenVal))
+
+	// the value is still in the memtable
+	mayExist, value := db.KeyMayExist(ro, givenKey)
+	ensure.True(t, mayExist)
+	ensure.DeepEqual(t, value, givenVal)
+
+	ensure.DeepEqual(t, db.KeysMayExist(ro, [][]byte{givenKey}), []bool{true})
+	ensure.DeepEqual(t, db.KeysMayExist(ro, nil), []bool{})
+}
+
+func TestDBKeyMayExistCF(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBKeyMayExistCF")
+	defer cleanup()
+
+	var (
+		givenKey = []byte("hello")
+		givenVal = []byte("world")
+		wo       = NewDefaultWriteOptions()
+		ro       = NewDefaultReadOptions()
+	)
+	ensure.Nil(t, db.PutCF(wo, cfh[1], givenKey, givenVal))
+
+	mayExist, value := db.KeyMayExistCF(ro, cfh[1], givenKey)
+	ensure.True(t, mayExist)
+	ensure.DeepEqual(t, value, givenVal)
+
+	ensure.DeepEqual(t, db.KeysMayExistCF(ro, cfh[1], [][]byte{givenKey}), []bool{true})
+}
+
+func TestDBAutoCompactionControl(t *testing.T) {
+	db := newTestDB(t, "TestDBAutoCompactionControl", func(opts *Options) {
+		opts.SetLevel0FileNumCompactionTrigger(2)
+	})
+	defer db.Close()
+
+	ensure.Nil(t, db.DisableAutoCompaction())
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 4; i++ {
+		ensure.Nil(t, db.Put(wo, []byte("key"+strconv.Itoa(i)), []byte("val")))
+		ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	}
+	ensure.Nil(t, db.WaitForCompact(10*time.Second))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "4")
+
+	ensure.Nil(t, db.EnableAutoCompaction())
+	ensure.Nil(t, db.WaitForCompact(10*time.Second))
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+
+	// manual compactions return immediately while disabled
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	db.DisableManualCompaction()
+	db.CompactRange(Range{nil, nil})
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "1")
+	compactOpts := NewDefaultCompactRangeOptions()
+	defer compactOpts.Destroy()
+	ensure.DeepEqual(t, db.CompactRangeOpt(Range{nil, nil}, compactOpts), ErrManualCompactionDisabled)
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "1")
+	db.EnableManualCompaction()
+	db.CompactRange(Range{nil, nil})
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "0")
+
+	// an unbalanced EnableManualCompaction doesn't undo the next disable
+	db.EnableManualCompaction()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+	db.DisableManualCompaction()
+	db.CompactRange(Range{nil, nil})
+	ensure.DeepEqual(t, db.GetProperty("rocksdb.num-files-at-level0"), "1")
+	db.EnableManualCompaction()
+}
+
+func TestDBPauseBackgroundWork(t *testing.T) {
+	db := newTestDB(t, "TestDBPauseBackgroundWork", func(opts *Options) {
+		opts.SetWriteBufferSize(64 << 10)
+		opts.SetMaxWriteBufferNumber(2)
+	})
+	defer db.Close()
+
+	// while paused, the memtables are not flushed and writes stop once they
+	// are full
+	ensure.Nil(t, db.PauseBackgroundWork())
+	wo := NewDefaultWriteOptions()
+	wo.SetNoSlowdown(true)
+	value := make([]byte, 1024)
+	var err error
+	for i := 0; i < 10000 && err == nil; i++ {
+		err = db.Put(wo, []byte("key"+strconv.Itoa(i)), value)
+	}
+	ensure.NotNil(t, err)
+	ensure.StringContains(t, err.Error(), "Write stall")
+
+	// the writes resume once the memtables are flushed
+	ensure.Nil(t, db.ContinueBackgroundWork())
+	ensure.Nil(t, db.WaitForCompact(10*time.Second))
+	ensure.Nil(t, db.Put(wo, []byte("key"), value))
+}
+
+func TestDBCancelAllBackgroundWork(t *testing.T) {
+	db := newTestDB(t, "TestDBCancelAllBackgroundWork", func(opts *Options) {
+		opts.SetWriteBufferSize(64 << 10)
+		opts.SetMaxWriteBufferNumber(2)
+	})
+	defer db.Close()
+
+	// once the background work is cancelled for good, the memtables are
+	// never flushed and writes stop once they are full.
+	db.CancelAllBackgroundWork(true)
+	wo := NewDefaultWriteOptions()
+	wo.SetNoSlowdown(true)
+	value := make([]byte, 1024)
+	var err error
+	for i := 0; i < 10000 && err == nil; i++ {
+		err = db.Put(wo, []byte("key"+strconv.Itoa(i)), value)
+	}
+	ensure.NotNil(t, err)
+	ensure.StringContains(t, err.Error(), "Write stall")
+}
+
+func TestDBSetMutableCFOptions(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBSetMutableCFOptions")
+	defer cleanup()
+
+	var (
+		writeBufferSize = uint64(8 << 20)
+		slowdown        = 30
+		stop            = 40
+		disable         = true
+		compression     = LZ4Compression
+	)
+	ensure.Nil(t, db.SetMutableCFOptions(cfh[1], &MutableCFOptions{
+		WriteBufferSize:             &writeBufferSize,
+		Level0SlowdownWritesTrigger: &slowdown,
+		Level0StopWritesTrigger:     &stop,
+		DisableAutoCompactions:      &disable,
+		Compression:                 &compression,
+	}))
+	ensure.Nil(t, db.SetMutableCFOptions(nil, &MutableCFOptions{WriteBufferSize: &writeBufferSize}))
+
+	// the triggers are checked before anything is sent to the database
+	err := db.SetMutableCFOptions(cfh[1], &MutableCFOptions{
+		Level0SlowdownWritesTrigger: &stop,
+		Level0StopWritesTrigger:     &slowdown,
+	})
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, err.(ConfigErrors)[0].Field, "level0_stop_writes_trigger")
+
+	err = db.SetOptionsCF(cfh[1], []string{"no_such_option"}, []string{"1"})
+	ensure.NotNil(t, err)
+
+	// the default bottommost compression can be restored, but isn't a
+	// compression of its own
+	disabled := DisableCompressionOption
+	ensure.Nil(t, db.SetMutableCFOptions(cfh[1], &MutableCFOptions{BottommostCompression: &disabled}))
+	err = db.SetMutableCFOptions(cfh[1], &MutableCFOptions{Compression: &disabled})
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, err.(ConfigErrors)[0].Field, "compression")
+}
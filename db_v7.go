@@ -0,0 +1,155 @@
+//go:build v7
+// +build v7
+
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+)
+
+// ColumnFamilyMetadata describes the LSM tree of a column family.
+type ColumnFamilyMetadata struct {
+	Name      string
+	Size      uint64
+	FileCount int
+	// Levels holds all the levels of the column family, including the empty
+	// ones, from level 0.
+	Levels []LevelMetadata
+}
+
+// LevelMetadata describes a level of the LSM tree of a column family.
+type LevelMetadata struct {
+	Level int
+	Size  uint64
+	Files []SstFileMetadata
+}
+
+// SstFileMetadata describes a SST file of a level.
+//
+// The C API doesn't report the smallest and largest sequence numbers of the
+// files, nor whether they are being compacted, so they have no field.
+type SstFileMetadata struct {
+	// Name is the name of the file, relative to the database directory.
+	Name         string
+	Size         uint64
+	SmallestKey  []byte
+	LargestKey   []byte
+	NumEntries   uint64
+	NumDeletions uint64
+}
+
+// GetColumnFamilyMetaData returns the metadata of the column family, or of the
+// default column family if cf is nil, with the size and files of each level.
+func (db *DB) GetColumnFamilyMetaData(cf *ColumnFamilyHandle) ColumnFamilyMetadata {
+	var cMeta *C.rocksdb_column_family_metadata_t
+	if cf == nil {
+		cMeta = C.rocksdb_get_column_family_metadata(db.c)
+	} else {
+		cMeta = C.rocksdb_get_column_family_metadata_cf(db.c, cf.c)
+	}
+	defer C.rocksdb_column_family_metadata_destroy(cMeta)
+
+	cName := C.rocksdb_column_family_metadata_get_name(cMeta)
+	meta := ColumnFamilyMetadata{
+		Name:      C.GoString(cName),
+		Size:      uint64(C.rocksdb_column_family_metadata_get_size(cMeta)),
+		FileCount: int(C.rocksdb_column_family_metadata_get_file_count(cMeta)),
+		Levels:    make([]LevelMetadata, int(C.rocksdb_column_family_metadata_get_level_count(cMeta))),
+	}
+	C.free(unsafe.Pointer(cName))
+
+	// the number of entries of the files are only found in the live files
+	liveFiles := make(map[string]LiveFileMetadata)
+	for _, file := range db.GetLiveFilesMetaData() {
+		if file.ColumnFamilyName == meta.Name {
+			liveFiles[strings.TrimPrefix(file.Name, "/")] = file
+		}
+	}
+
+	for i := range meta.Levels {
+		cLevel := C.rocksdb_column_family_metadata_get_level_metadata(cMeta, C.size_t(i))
+		level := &meta.Levels[i]
+		level.Level = int(C.rocksdb_level_metadata_get_level(cLevel))
+		level.Size = uint64(C.rocksdb_level_metadata_get_size(cLevel))
+		level.Files = make([]SstFileMetadata, int(C.rocksdb_level_metadata_get_file_count(cLevel)))
+		for j := range level.Files {
+			cFile := C.rocksdb_level_metadata_get_sst_file_metadata(cLevel, C.size_t(j))
+			level.Files[j] = newSstFileMetadata(cFile)
+			C.rocksdb_sst_file_metadata_destroy(cFile)
+
+			if live, ok := liveFiles[level.Files[j].Name]; ok {
+				level.Files[j].NumEntries = live.NumEntries
+				level.Files[j].NumDeletions = live.NumDeletions
+			}
+		}
+		C.rocksdb_level_metadata_destroy(cLevel)
+	}
+	return meta
+}
+
+// GetPropertiesOfAllTables returns the properties of the live SST files of the
+// column family, or of the default column family if cf is nil, by the name of
+// the files relative to the database directory. The files deleted by a
+// compaction while they are read are left out. A file in a format
+// ReadTableProperties doesn't support returns an error wrapping
+// ErrTableNotSupported.
+//
+// The C API doesn't tell which directory a file is in, so the files are read
+// from the database directory: the databases with db_paths or cf_paths are
+// not supported, and their files not found there are reported as errors.
+func (db *DB) GetPropertiesOfAllTables(cf *ColumnFamilyHandle) (map[string]*TableProperties, error) {
+	name := "default"
+	if cf != nil {
+		name = cf.Name()
+	}
+
+	props := make(map[string]*TableProperties)
+	missing := make(map[string]error)
+	for _, file := range db.GetLiveFilesMetaData() {
+		if file.ColumnFamilyName != name {
+			continue
+		}
+		fileName := strings.TrimPrefix(file.Name, "/")
+		p, err := ReadTableProperties(filepath.Join(db.Name(), fileName))
+		if os.IsNotExist(err) {
+			missing[file.Name] = err
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		props[fileName] = p
+	}
+	if len(missing) > 0 {
+		// the files still live are not in the database directory
+		for _, file := range db.GetLiveFilesMetaData() {
+			if err, ok := missing[file.Name]; ok {
+				return nil, err
+			}
+		}
+	}
+	return props, nil
+}
+
+func newSstFileMetadata(cFile *C.rocksdb_sst_file_metadata_t) SstFileMetadata {
+	var file SstFileMetadata
+
+	cName := C.rocksdb_sst_file_metadata_get_relative_filename(cFile)
+	file.Name = strings.TrimPrefix(C.GoString(cName), "/")
+	C.free(unsafe.Pointer(cName))
+	file.Size = uint64(C.rocksdb_sst_file_metadata_get_size(cFile))
+
+	var cLen C.size_t
+	cKey := C.rocksdb_sst_file_metadata_get_smallestkey(cFile, &cLen)
+	file.SmallestKey = C.GoBytes(unsafe.Pointer(cKey), C.int(cLen))
+	C.free(unsafe.Pointer(cKey))
+	cKey = C.rocksdb_sst_file_metadata_get_largestkey(cFile, &cLen)
+	file.LargestKey = C.GoBytes(unsafe.Pointer(cKey), C.int(cLen))
+	C.free(unsafe.Pointer(cKey))
+	return file
+}
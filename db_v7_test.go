@@ -0,0 +1,73 @@
+//go:build v7
+// +build v7
+
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDBGetColumnFamilyMetaData(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBGetColumnFamilyMetaData")
+	defer cleanup()
+
+	ensure.DeepEqual(t, cfh[1].Name(), "guide")
+	ensure.True(t, cfh[0].ID() != cfh[1].ID())
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("a"), []byte("1")))
+	ensure.Nil(t, db.Put(wo, []byte("b"), []byte("2")))
+	ensure.Nil(t, db.Delete(wo, []byte("c")))
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	ensure.Nil(t, db.Flush(fo))
+
+	meta := db.GetColumnFamilyMetaData(nil)
+	ensure.DeepEqual(t, meta.Name, "default")
+	ensure.DeepEqual(t, meta.FileCount, 1)
+	ensure.DeepEqual(t, len(meta.Levels), 7)
+
+	level := meta.Levels[0]
+	ensure.DeepEqual(t, level.Level, 0)
+	ensure.DeepEqual(t, len(level.Files), 1)
+	file := level.Files[0]
+	ensure.DeepEqual(t, level.Size, file.Size)
+	ensure.DeepEqual(t, file.SmallestKey, []byte("a"))
+	ensure.DeepEqual(t, file.LargestKey, []byte("c"))
+	ensure.DeepEqual(t, file.NumEntries, uint64(3))
+	ensure.DeepEqual(t, file.NumDeletions, uint64(1))
+
+	ensure.DeepEqual(t, db.GetColumnFamilyMetaData(cfh[0]), meta)
+	ensure.DeepEqual(t, db.GetColumnFamilyMetaData(cfh[1]).FileCount, 0)
+}
+
+func TestDBGetPropertiesOfAllTables(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestDBGetPropertiesOfAllTables")
+	defer cleanup()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("a"), []byte("1")))
+	ensure.Nil(t, db.Delete(wo, []byte("b")))
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	ensure.Nil(t, db.Flush(fo))
+
+	props, err := db.GetPropertiesOfAllTables(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(props), 1)
+	file := db.GetColumnFamilyMetaData(nil).Levels[0].Files[0]
+	p, ok := props[file.Name]
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, p.NumEntries, uint64(2))
+	ensure.DeepEqual(t, p.NumDeletions, uint64(1))
+	ensure.DeepEqual(t, p.ColumnFamilyName, "default")
+	ensure.DeepEqual(t, p.ComparatorName, "leveldb.BytewiseComparator")
+
+	props, err = db.GetPropertiesOfAllTables(cfh[1])
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(props), 0)
+}
@@ -15,7 +15,9 @@ func NewDefaultEnv() *Env {
 
 // NewNativeEnv creates a Environment object.
 func NewNativeEnv(c *C.rocksdb_env_t) *Env {
-	return &Env{c}
+	env := &Env{c}
+	trackAlloc("Env", env)
+	return env
 }
 
 // SetBackgroundThreads sets the number of background worker threads
@@ -35,6 +37,7 @@ func (env *Env) SetHighPriorityBackgroundThreads(n int) {
 
 // Destroy deallocates the Env object.
 func (env *Env) Destroy() {
+	trackFree(env)
 	C.rocksdb_env_destroy(env.c)
 	env.c = nil
 }
@@ -16,6 +16,59 @@ rocksdb_comparator_t* gorocksdb_comparator_create(uintptr_t idx) {
         (const char *(*)(void*))(gorocksdb_comparator_name));
 }
 
+static int gorocksdb_bytewise_compare(const char* a, size_t a_len, const char* b, size_t b_len) {
+    int r = memcmp(a, b, a_len < b_len ? a_len : b_len);
+    if (r == 0) {
+        r = a_len < b_len ? -1 : (a_len > b_len ? 1 : 0);
+    }
+    return r;
+}
+
+static int gorocksdb_reverse_bytewise_comparator_compare(void* state, const char* a, size_t a_len, const char* b, size_t b_len) {
+    return -gorocksdb_bytewise_compare(a, a_len, b, b_len);
+}
+
+static const char* gorocksdb_reverse_bytewise_comparator_name(void* state) {
+    return "rocksdb.ReverseBytewiseComparator";
+}
+
+rocksdb_comparator_t* gorocksdb_reverse_bytewise_comparator_create() {
+    return rocksdb_comparator_create(
+        NULL,
+        gorocksdb_destruct_handler,
+        gorocksdb_reverse_bytewise_comparator_compare,
+        gorocksdb_reverse_bytewise_comparator_name);
+}
+
+static uint64_t gorocksdb_decode_little_endian64(const char* p) {
+    const unsigned char* u = (const unsigned char*)p;
+    uint64_t v = 0;
+    for (int i = 7; i >= 0; i--) {
+        v = (v << 8) | u[i];
+    }
+    return v;
+}
+
+static int gorocksdb_uint64_little_endian_comparator_compare(void* state, const char* a, size_t a_len, const char* b, size_t b_len) {
+    if (a_len != 8 || b_len != 8) {
+        return gorocksdb_bytewise_compare(a, a_len, b, b_len);
+    }
+    uint64_t x = gorocksdb_decode_little_endian64(a), y = gorocksdb_decode_little_endian64(b);
+    return x < y ? -1 : (x > y ? 1 : 0);
+}
+
+static const char* gorocksdb_uint64_little_endian_comparator_name(void* state) {
+    return "gorocksdb.Uint64LittleEndianComparator";
+}
+
+rocksdb_comparator_t* gorocksdb_uint64_little_endian_comparator_create() {
+    return rocksdb_comparator_create(
+        NULL,
+        gorocksdb_destruct_handler,
+        gorocksdb_uint64_little_endian_comparator_compare,
+        gorocksdb_uint64_little_endian_comparator_name);
+}
+
 /* CompactionFilter */
 
 rocksdb_compactionfilter_t* gorocksdb_compactionfilter_create(uintptr_t idx) {
@@ -26,6 +79,24 @@ rocksdb_compactionfilter_t* gorocksdb_compactionfilter_create(uintptr_t idx) {
         (const char *(*)(void*))(gorocksdb_compactionfilter_name));
 }
 
+/* CompactionFilterFactory */
+
+rocksdb_compactionfilterfactory_t* gorocksdb_compactionfilterfactory_create(uintptr_t idx) {
+    return rocksdb_compactionfilterfactory_create(
+        (void*)idx,
+        gorocksdb_destruct_handler,
+        (rocksdb_compactionfilter_t* (*)(void*, rocksdb_compactionfiltercontext_t*))(gorocksdb_compactionfilterfactory_create_filter),
+        (const char *(*)(void*))(gorocksdb_compactionfilterfactory_name));
+}
+
+rocksdb_compactionfilter_t* gorocksdb_compactionfilterv2_create(uintptr_t id) {
+    return rocksdb_compactionfilter_create(
+        (void*)id,
+        (void (*)(void*))(gorocksdb_compactionfilterv2_destruct),
+        (unsigned char (*)(void*, int, const char*, size_t, const char*, size_t, char**, size_t*, unsigned char*))(gorocksdb_compactionfilterv2_filter),
+        (const char *(*)(void*))(gorocksdb_compactionfilterv2_name));
+}
+
 /* Filter Policy */
 
 rocksdb_filterpolicy_t* gorocksdb_filterpolicy_create(uintptr_t idx) {
@@ -72,11 +143,71 @@ rocksdb_slicetransform_t* gorocksdb_slicetransform_create(uintptr_t idx) {
 
 #define DEFAULT_PAGE_ALLOC_SIZE 512
 
+// gorocksdb_key_in_range checks the key against the prefix and end key of the filter.
+static bool gorocksdb_key_in_range(const char* key, size_t key_size, bool reverse, const gorocksdb_many_keys_filter_t* key_filter) {
+    size_t cmp_size;
+
+    if (key_filter->key_prefix_s > 0) {
+        if (key_size < key_filter->key_prefix_s) {
+            return FALSE;
+        }
+        if (memcmp(key_filter->key_prefix, key, key_filter->key_prefix_s) != 0) {
+            return FALSE;
+        }
+    }
+    if (key_filter->key_end_s > 0) {
+        cmp_size = key_size > key_filter->key_end_s ? key_filter->key_end_s : key_size;
+        int c = memcmp(key, key_filter->key_end, cmp_size);
+        if (c == 0 && key_filter->key_end_s == key_size) {
+            // keys are equals, we break
+            return FALSE;
+        }
+        if (reverse) {
+            if (c == 0 && key_filter->key_end_s > key_size) {
+                // key_end is bigger than key, we must stop
+                return FALSE;
+            } else if (c < 0) {
+                // key is smaller than key_end, we break
+                return FALSE;
+            }
+        } else {
+            if (c == 0 && key_size > key_filter->key_end_s) {
+                // key_end is smaller than key, we must stop
+                return FALSE;
+            } else if (c > 0) {
+                // key is greater than key_end, we break
+                return FALSE;
+            }
+        }
+    }
+    return TRUE;
+}
+
+// gorocksdb_value_matches checks the value against the value prefix and suffix of the filter.
+static bool gorocksdb_value_matches(const char* val, size_t value_size, const gorocksdb_many_keys_filter_t* key_filter) {
+    if (key_filter->value_prefix_s > 0) {
+        if (value_size < key_filter->value_prefix_s ||
+            memcmp(key_filter->value_prefix, val, key_filter->value_prefix_s) != 0) {
+            return FALSE;
+        }
+    }
+    if (key_filter->value_suffix_s > 0) {
+        if (value_size < key_filter->value_suffix_s ||
+            memcmp(key_filter->value_suffix, val + value_size - key_filter->value_suffix_s, key_filter->value_suffix_s) != 0) {
+            return FALSE;
+        }
+    }
+    return TRUE;
+}
+
 extern gorocksdb_many_keys_t* gorocksdb_iter_many_keys(rocksdb_iterator_t* iter, int limit, bool reverse, const gorocksdb_many_keys_filter_t* key_filter, int page_alloc_size) {
     int i;
     char** keys, **values;
     size_t* key_sizes, *value_sizes;
-    size_t key_size, value_size, cmp_size;
+    size_t key_size, value_size, entry_size;
+    size_t total_bytes = 0;
+    bool filter_values = key_filter->value_prefix_s > 0 || key_filter->value_suffix_s > 0;
+    bool stopped = FALSE;
 
     // todo: we malloc the prefetch size (improve it)
     gorocksdb_many_keys_t* many_keys = (gorocksdb_many_keys_t*) malloc(sizeof(gorocksdb_many_keys_t));
@@ -99,40 +230,43 @@ extern gorocksdb_many_keys_t* gorocksdb_iter_many_keys(rocksdb_iterator_t* iter,
         const char* key = rocksdb_iter_key(iter, &key_size);
 
         // Check filter
-        if (key_filter->key_prefix_s > 0) {
-            if (key_size < key_filter->key_prefix_s) {
-                break;
-            }
-            if (memcmp(key_filter->key_prefix, key, key_filter->key_prefix_s) != 0) {
-                break;
-            }
+        if (!gorocksdb_key_in_range(key, key_size, reverse, key_filter)) {
+            break;
         }
-        if (key_filter->key_end_s > 0) {
-            cmp_size = key_size > key_filter->key_end_s ? key_filter->key_end_s : key_size;
-            int c = memcmp(key, key_filter->key_end, cmp_size);
-            if (c == 0 && key_filter->key_end_s == key_size) {
-                // keys are equals, we break
-                break;
-            }
+
+        // Check limit
+        if (limit > 0 && i == limit) {
+            stopped = TRUE;
+            break;
+        }
+
+        // Get value and check filter
+        const char* val = NULL;
+        value_size = 0;
+        if (!key_filter->keys_only || filter_values) {
+            val = rocksdb_iter_value(iter, &value_size);
+        }
+        if (filter_values && !gorocksdb_value_matches(val, value_size, key_filter)) {
             if (reverse) {
-                if (c == 0 && key_filter->key_end_s > key_size) {
-                    // key_end is bigger than key, we must stop
-                    break;
-                } else if (c < 0) {
-                    // key is smaller than key_end, we break
-                    break;
-                }
+                rocksdb_iter_prev(iter);
             } else {
-                if (c == 0 && key_size > key_filter->key_end_s) {
-                    // key_end is smaller than key, we must stop
-                    break;
-                } else if (c > 0) {
-                    // key is greater than key_end, we break
-                    break;
-                }
+                rocksdb_iter_next(iter);
             }
+            continue;
+        }
+        if (key_filter->keys_only) {
+            val = NULL;
+            value_size = 0;
         }
 
+        // Check bytes budget, always returning at least one key
+        entry_size = key_size + value_size;
+        if (key_filter->max_bytes > 0 && i > 0 && total_bytes + entry_size > key_filter->max_bytes) {
+            stopped = TRUE;
+            break;
+        }
+        total_bytes += entry_size;
+
         // Store key
         if (i == size) {
             // realloc 2x existing size
@@ -146,8 +280,7 @@ extern gorocksdb_many_keys_t* gorocksdb_iter_many_keys(rocksdb_iterator_t* iter,
         memcpy(keys[i], key, key_size);
         key_sizes[i] = key_size;
 
-        // Get value and store it
-        const char* val = rocksdb_iter_value(iter, &value_size);
+        // Store value
         if (val != NULL) {
             values[i] = (char*) malloc(value_size * sizeof(char));
             memcpy(values[i], val, value_size);
@@ -165,11 +298,16 @@ extern gorocksdb_many_keys_t* gorocksdb_iter_many_keys(rocksdb_iterator_t* iter,
             // Move next
             rocksdb_iter_next(iter);
         }
+    }
 
-        // Check limit
-        if (limit > 0 && i == limit) {
-            break;
-        }
+    many_keys->next_key = NULL;
+    many_keys->next_key_s = 0;
+    if (stopped) {
+        const char* key = rocksdb_iter_key(iter, &key_size);
+        // never NULL, even for an empty key
+        many_keys->next_key = (char*) malloc((key_size + 1) * sizeof(char));
+        memcpy(many_keys->next_key, key, key_size);
+        many_keys->next_key_s = key_size;
     }
 
     many_keys->keys = keys;
@@ -190,6 +328,7 @@ extern void gorocksdb_destroy_many_keys(gorocksdb_many_keys_t* many_keys) {
     }
     free(many_keys->keys);
     free(many_keys->key_sizes);
+    free(many_keys->next_key);
     if (many_keys->values != NULL) {
         free(many_keys->values);
         free(many_keys->value_sizes);
@@ -236,6 +375,12 @@ extern gorocksdb_many_keys_t** gorocksdb_many_search_keys(rocksdb_iterator_t* it
     	key_filter.key_prefix_s = keys_searches[i].key_prefix_s;
     	key_filter.key_end = keys_searches[i].key_end;
     	key_filter.key_end_s = keys_searches[i].key_end_s;
+    	key_filter.keys_only = keys_searches[i].keys_only;
+    	key_filter.max_bytes = keys_searches[i].max_bytes;
+    	key_filter.value_prefix = keys_searches[i].value_prefix;
+    	key_filter.value_prefix_s = keys_searches[i].value_prefix_s;
+    	key_filter.value_suffix = keys_searches[i].value_suffix;
+    	key_filter.value_suffix_s = keys_searches[i].value_suffix_s;
     	result[i] = gorocksdb_iter_many_keys(iter, keys_searches[i].limit, keys_searches[i].reverse, &key_filter, page_alloc_size);
     }
     return result;
@@ -264,6 +409,7 @@ extern gorocksdb_many_keys_t** gorocksdb_many_search_keys_raw(
 ) {
     int i;
     gorocksdb_many_keys_filter_t key_filter;
+    memset(&key_filter, 0, sizeof(key_filter));
     gorocksdb_many_keys_t** result = (gorocksdb_many_keys_t**) malloc(size*sizeof(gorocksdb_many_keys_t*));
     for (i=0; i < size; i++) {
     	rocksdb_iter_seek(iter, key_froms[i], key_from_s[i]);
@@ -276,6 +422,26 @@ extern gorocksdb_many_keys_t** gorocksdb_many_search_keys_raw(
     return result;
 }
 
+void gorocksdb_multi_get_pinned(
+    rocksdb_t* db,
+    const rocksdb_readoptions_t* options,
+    rocksdb_column_family_handle_t* cf,
+    size_t num_keys,
+    char** keys,
+    size_t* key_sizes,
+    rocksdb_pinnableslice_t** values,
+    char** errs
+) {
+    int i;
+    for (i=0; i < num_keys; i++) {
+        if (cf == NULL) {
+            values[i] = rocksdb_get_pinned(db, options, keys[i], key_sizes[i], &errs[i]);
+        } else {
+            values[i] = rocksdb_get_pinned_cf(db, options, cf, keys[i], key_sizes[i], &errs[i]);
+        }
+    }
+}
+
 void gorocksdb_writebatch_put_many(
     rocksdb_writebatch_t* batch,
     size_t num_pairs,
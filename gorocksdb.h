@@ -7,6 +7,10 @@ typedef struct {
     char** values;
     size_t* value_sizes;
     int found;
+    // the key to resume from when the search stopped on its limit or its
+    // bytes budget, NULL when there are no more keys.
+    char* next_key;
+    size_t next_key_s;
 
 } gorocksdb_many_keys_t;
 
@@ -25,9 +29,16 @@ extern void gorocksdb_destruct_handler(void* state);
 
 extern rocksdb_compactionfilter_t* gorocksdb_compactionfilter_create(uintptr_t idx);
 
+/* CompactionFilterFactory */
+
+extern rocksdb_compactionfilterfactory_t* gorocksdb_compactionfilterfactory_create(uintptr_t idx);
+extern rocksdb_compactionfilter_t* gorocksdb_compactionfilterv2_create(uintptr_t id);
+
 /* Comparator */
 
 extern rocksdb_comparator_t* gorocksdb_comparator_create(uintptr_t idx);
+extern rocksdb_comparator_t* gorocksdb_reverse_bytewise_comparator_create();
+extern rocksdb_comparator_t* gorocksdb_uint64_little_endian_comparator_create();
 
 /* Filter Policy */
 
@@ -50,6 +61,12 @@ typedef struct {
     size_t key_prefix_s;
     char* key_end;
     size_t key_end_s;
+    bool keys_only;
+    size_t max_bytes;
+    char* value_prefix;
+    size_t value_prefix_s;
+    char* value_suffix;
+    size_t value_suffix_s;
 
 } gorocksdb_many_keys_filter_t;
 
@@ -69,6 +86,12 @@ typedef struct {
     int limit;
     bool reverse;
     bool exclude_key_from;
+    bool keys_only;
+    size_t max_bytes;
+    char* value_prefix;
+    size_t value_prefix_s;
+    char* value_suffix;
+    size_t value_suffix_s;
 
 } gorocksdb_keys_search_t;
 
@@ -95,6 +118,19 @@ extern gorocksdb_many_keys_t** gorocksdb_many_search_keys_raw(
 
 extern void gorocksdb_destroy_many_many_keys(gorocksdb_many_keys_t** many_many_keys, int size);
 
+/* Batch GetPinned */
+
+extern void gorocksdb_multi_get_pinned(
+    rocksdb_t* db,
+    const rocksdb_readoptions_t* options,
+    rocksdb_column_family_handle_t* cf,
+    size_t num_keys,
+    char** keys,
+    size_t* key_sizes,
+    rocksdb_pinnableslice_t** values,
+    char** errs
+);
+
 /* Batch PutMany */
 
 void gorocksdb_writebatch_put_many( 
@@ -115,3 +151,23 @@ void gorocksdb_writebatch_put_many_cf(
     char** values, 
     size_t* value_sizes
 );
+
+/* Background work */
+
+extern void gorocksdb_pause_background_work(rocksdb_t* db, char** errptr);
+
+extern void gorocksdb_continue_background_work(rocksdb_t* db, char** errptr);
+
+/* SST file reader */
+
+typedef struct gorocksdb_sstfilereader_t gorocksdb_sstfilereader_t;
+
+extern gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts);
+
+extern void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* name, char** errptr);
+
+extern rocksdb_iterator_t* gorocksdb_sstfilereader_create_iterator(gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts);
+
+extern void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr);
+
+extern void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader);
@@ -0,0 +1,27 @@
+// The C API has no access to DB::PauseBackgroundWork and
+// DB::ContinueBackgroundWork, so they are called on the DB held by
+// rocksdb_t, declared here as in RocksDB's c.cc since c.h keeps it opaque.
+
+#include <stdlib.h>
+#include <string.h>
+
+#include "rocksdb/c.h"
+#include "rocksdb/db.h"
+
+struct rocksdb_t {
+    rocksdb::DB* rep;
+};
+
+static void gorocksdb_save_error(const rocksdb::Status& s, char** errptr) {
+    if (!s.ok()) {
+        *errptr = strdup(s.ToString().c_str());
+    }
+}
+
+extern "C" void gorocksdb_pause_background_work(rocksdb_t* db, char** errptr) {
+    gorocksdb_save_error(db->rep->PauseBackgroundWork(), errptr);
+}
+
+extern "C" void gorocksdb_continue_background_work(rocksdb_t* db, char** errptr) {
+    gorocksdb_save_error(db->rep->ContinueBackgroundWork(), errptr);
+}
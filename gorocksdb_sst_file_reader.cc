@@ -0,0 +1,62 @@
+//go:build v6
+// +build v6
+
+// The C API has no SST file reader, so RocksDB's SstFileReader is wrapped
+// here. It is given the options and read options held by rocksdb_options_t
+// and rocksdb_readoptions_t, and its iterators are returned in a
+// rocksdb_iterator_t, declared here as in RocksDB's c.cc since c.h keeps them
+// opaque. Only the first member of rocksdb_readoptions_t is used, so the
+// iterate bounds it holds after it are left out.
+
+#include <stdlib.h>
+#include <string.h>
+
+#include "rocksdb/c.h"
+#include "rocksdb/sst_file_reader.h"
+
+struct rocksdb_options_t {
+    rocksdb::Options rep;
+};
+
+struct rocksdb_readoptions_t {
+    rocksdb::ReadOptions rep;
+};
+
+struct rocksdb_iterator_t {
+    rocksdb::Iterator* rep;
+};
+
+struct gorocksdb_sstfilereader_t {
+    rocksdb::SstFileReader* rep;
+};
+
+static void gorocksdb_save_error(const rocksdb::Status& s, char** errptr) {
+    if (!s.ok()) {
+        *errptr = strdup(s.ToString().c_str());
+    }
+}
+
+extern "C" gorocksdb_sstfilereader_t* gorocksdb_sstfilereader_create(const rocksdb_options_t* opts) {
+    gorocksdb_sstfilereader_t* reader = new gorocksdb_sstfilereader_t;
+    reader->rep = new rocksdb::SstFileReader(opts->rep);
+    return reader;
+}
+
+extern "C" void gorocksdb_sstfilereader_open(gorocksdb_sstfilereader_t* reader, const char* name, char** errptr) {
+    gorocksdb_save_error(reader->rep->Open(std::string(name)), errptr);
+}
+
+extern "C" rocksdb_iterator_t* gorocksdb_sstfilereader_create_iterator(gorocksdb_sstfilereader_t* reader, const rocksdb_readoptions_t* opts) {
+    rocksdb_iterator_t* iter = new rocksdb_iterator_t;
+    iter->rep = reader->rep->NewIterator(opts->rep);
+    return iter;
+}
+
+extern "C" void gorocksdb_sstfilereader_verify_checksum(gorocksdb_sstfilereader_t* reader, char** errptr) {
+    gorocksdb_save_error(reader->rep->VerifyChecksum(), errptr);
+}
+
+extern "C" void gorocksdb_sstfilereader_destroy(gorocksdb_sstfilereader_t* reader) {
+    delete reader->rep;
+    delete reader;
+}
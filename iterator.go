@@ -30,12 +30,57 @@ import (
 //      }
 //
 type Iterator struct {
-	c *C.rocksdb_iterator_t
+	c      *C.rocksdb_iterator_t
+	pinned bool
+
+	// opts are the read options the iterator was created with, destroyed
+	// with the iterator when ownsOpts is true.
+	opts     *ReadOptions
+	ownsOpts bool
+	// create recreates the underlying iterator on Refresh.
+	create func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t
 }
 
 // NewNativeIterator creates a Iterator object.
 func NewNativeIterator(c unsafe.Pointer) *Iterator {
-	return &Iterator{(*C.rocksdb_iterator_t)(c)}
+	iter := &Iterator{c: (*C.rocksdb_iterator_t)(c)}
+	trackAlloc("Iterator", iter)
+	return iter
+}
+
+func newIterator(
+	opts *ReadOptions,
+	ownsOpts bool,
+	create func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t,
+) *Iterator {
+	iter := NewNativeIterator(unsafe.Pointer(create(opts.c)))
+	iter.pinned = opts.pinData
+	iter.opts = opts
+	iter.ownsOpts = ownsOpts
+	iter.create = create
+	return iter
+}
+
+// Refresh updates the iterator to read the latest state of the database,
+// as if it was recreated with the same ReadOptions. The iterator is not
+// positioned afterwards, so one of the Seek methods must be called.
+// If the ReadOptions have a snapshot, the iterator keeps reading from it.
+//
+// Only the iterators created by the NewIterator methods of this package
+// can be refreshed, and their ReadOptions must not have been destroyed.
+func (iter *Iterator) Refresh() error {
+	if iter.c == nil {
+		return errors.New("Invalid argument: the iterator is closed")
+	}
+	if iter.create == nil {
+		return errors.New("Not supported: the iterator was not created by gorocksdb")
+	}
+	if iter.opts.c == nil {
+		return errors.New("Invalid argument: the read options of the iterator were destroyed")
+	}
+	C.rocksdb_iter_destroy(iter.c)
+	iter.c = iter.create(iter.opts.c)
+	return nil
 }
 
 // Valid returns false only when an Iterator has iterated past either the
@@ -77,6 +122,41 @@ func (iter *Iterator) Value() *Slice {
 	return &Slice{cVal, cLen, true}
 }
 
+// Pinned reports whether the iterator was created with ReadOptions having
+// SetPinData(true), in which case KeyData and ValueData return pinned memory.
+func (iter *Iterator) Pinned() bool {
+	return iter.pinned
+}
+
+// KeyData returns the key the iterator currently holds without copying it.
+//
+// For a pinned iterator the returned bytes stay valid until the iterator is
+// closed, otherwise only until it is moved. The bytes must not be modified.
+func (iter *Iterator) KeyData() []byte {
+	var cLen C.size_t
+	cKey := C.rocksdb_iter_key(iter.c, &cLen)
+	if cKey == nil {
+		return nil
+	}
+	return charToByte(cKey, cLen)
+}
+
+// ValueData returns the value the iterator currently holds without copying it.
+//
+// For a pinned iterator the returned bytes stay valid until the iterator is
+// closed, as long as the value is read from a block based table or the
+// memtable. Values produced by a merge operator are only pinned until the
+// iterator is moved, so use Value().Data() and copy when merging is used.
+// The bytes must not be modified.
+func (iter *Iterator) ValueData() []byte {
+	var cLen C.size_t
+	cVal := C.rocksdb_iter_value(iter.c, &cLen)
+	if cVal == nil {
+		return nil
+	}
+	return charToByte(cVal, cLen)
+}
+
 // Next moves the iterator to the next sequential key in the database.
 func (iter *Iterator) Next() {
 	C.rocksdb_iter_next(iter.c)
@@ -126,8 +206,14 @@ func (iter *Iterator) Err() error {
 
 // Close closes the iterator.
 func (iter *Iterator) Close() {
+	trackFree(iter)
 	C.rocksdb_iter_destroy(iter.c)
 	iter.c = nil
+	if iter.ownsOpts {
+		iter.opts.Destroy()
+		iter.ownsOpts = false
+	}
+	iter.opts = nil
 }
 
 var ManyKeysPageAllocSize int = 512
@@ -171,16 +257,32 @@ type KeysSearch struct {
 	Limit          int
 	Reverse        bool
 	ExcludeKeyFrom bool
+	// KeysOnly skips copying the values, Values then returns nil values.
+	KeysOnly bool
+	// MaxBytes stops the search once the keys and values found would exceed
+	// it. At least one key is always returned. Zero or a negative value
+	// means no budget.
+	MaxBytes int
+	// ValuePrefix and ValueSuffix only keep the keys whose value starts
+	// or ends with them. Skipped keys don't count towards Limit.
+	ValuePrefix,
+	ValueSuffix []byte
 }
 
 func (iter *Iterator) ManySearchKeys(searches []KeysSearch) *ManyManyKeys {
 	nbSearches := len(searches)
 	cManyKeysSearches := make([]C.gorocksdb_keys_search_t, nbSearches)
 	for i := range searches {
+		maxBytes := searches[i].MaxBytes
+		if maxBytes < 0 {
+			maxBytes = 0
+		}
 		cKSearch := C.gorocksdb_keys_search_t{
 			limit:            C.int(searches[i].Limit),
 			reverse:          C.bool(btoi(searches[i].Reverse)),
 			exclude_key_from: C.bool(btoi(searches[i].ExcludeKeyFrom)),
+			keys_only:        C.bool(btoi(searches[i].KeysOnly)),
+			max_bytes:        C.size_t(maxBytes),
 		}
 		cKSearch.key_from = C.CString(string(searches[i].KeyFrom))
 		cKSearch.key_from_s = C.size_t(len(searches[i].KeyFrom))
@@ -192,6 +294,14 @@ func (iter *Iterator) ManySearchKeys(searches []KeysSearch) *ManyManyKeys {
 			cKSearch.key_end = C.CString(string(searches[i].KeyEnd))
 			cKSearch.key_end_s = C.size_t(len(searches[i].KeyEnd))
 		}
+		if len(searches[i].ValuePrefix) > 0 {
+			cKSearch.value_prefix = C.CString(string(searches[i].ValuePrefix))
+			cKSearch.value_prefix_s = C.size_t(len(searches[i].ValuePrefix))
+		}
+		if len(searches[i].ValueSuffix) > 0 {
+			cKSearch.value_suffix = C.CString(string(searches[i].ValueSuffix))
+			cKSearch.value_suffix_s = C.size_t(len(searches[i].ValueSuffix))
+		}
 		cManyKeysSearches[i] = cKSearch
 	}
 	cManyManyKeys := C.gorocksdb_many_search_keys(iter.c,
@@ -208,6 +318,12 @@ func (iter *Iterator) ManySearchKeys(searches []KeysSearch) *ManyManyKeys {
 		if len(searches[i].KeyEnd) > 0 {
 			C.free(unsafe.Pointer(cManyKeysSearches[i].key_end))
 		}
+		if len(searches[i].ValuePrefix) > 0 {
+			C.free(unsafe.Pointer(cManyKeysSearches[i].value_prefix))
+		}
+		if len(searches[i].ValueSuffix) > 0 {
+			C.free(unsafe.Pointer(cManyKeysSearches[i].value_suffix))
+		}
 	}
 	return &ManyManyKeys{c: cManyManyKeys, size: nbSearches}
 }
@@ -263,6 +379,17 @@ func (m *ManyKeys) Found() int {
 	return int(m.c.found)
 }
 
+// ResumeToken returns the key to use as KeyFrom of the next search when this
+// one stopped on its Limit or MaxBytes, or nil when there are no more keys.
+// The token is the first key that was not returned, so the next search must
+// not set ExcludeKeyFrom, which would skip it.
+func (m *ManyKeys) ResumeToken() []byte {
+	if m.c.next_key == nil {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(m.c.next_key), C.int(m.c.next_key_s))
+}
+
 func (m *ManyKeys) Keys() [][]byte {
 	found := m.Found()
 	keys := make([][]byte, found)
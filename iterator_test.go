@@ -30,6 +30,35 @@ func TestIterator(t *testing.T) {
 	ensure.DeepEqual(t, actualKeys, givenKeys)
 }
 
+func TestIteratorPinData(t *testing.T) {
+	db := newTestDB(t, "TestIteratorPinData", nil)
+	defer db.Close()
+
+	// insert keys
+	givenKeys := [][]byte{[]byte("key1"), []byte("key2"), []byte("key3")}
+	givenValues := [][]byte{[]byte("val1"), []byte("val2"), []byte("val3")}
+	wo := NewDefaultWriteOptions()
+	for i, k := range givenKeys {
+		ensure.Nil(t, db.Put(wo, k, givenValues[i]))
+	}
+
+	ro := NewDefaultReadOptions()
+	ro.SetPinData(true)
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+	ensure.True(t, iter.Pinned())
+
+	// pinned keys and values stay valid while the iterator moves
+	var actualKeys, actualValues [][]byte
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		actualKeys = append(actualKeys, iter.KeyData())
+		actualValues = append(actualValues, iter.ValueData())
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, actualKeys, givenKeys)
+	ensure.DeepEqual(t, actualValues, givenValues)
+}
+
 func TestIteratorNextManyWithKeyPrefix(t *testing.T) {
 	db := newTestDB(t, "TestIterator", nil)
 	defer db.Close()
@@ -651,3 +680,153 @@ func TestIteratorNextManyKeysEach(t *testing.T) {
 
 	manyKeys.Destroy()
 }
+
+func TestIteratorWithBoundsReuseReadOptions(t *testing.T) {
+	db := newTestDB(t, "TestIteratorWithBoundsReuseReadOptions", nil)
+	defer db.Close()
+
+	// insert keys
+	givenKeys := [][]byte{[]byte("key1"), []byte("key2"), []byte("key3"), []byte("key4")}
+	wo := NewDefaultWriteOptions()
+	for _, k := range givenKeys {
+		ensure.Nil(t, db.Put(wo, k, []byte("val")))
+	}
+
+	// the bounds of each iterator are its own, whatever happens to ro
+	ro := NewDefaultReadOptions()
+	ro.SetIterateUpperBound([]byte("key4"))
+	iters := []*Iterator{
+		db.NewIteratorWithBounds(ro, nil, []byte("key2")),
+		db.NewIteratorWithBounds(ro, []byte("key2"), []byte("key4")),
+		db.NewIteratorWithBounds(ro, []byte("key3"), nil),
+	}
+	ro.SetIterateLowerBound([]byte("key2"))
+	ro.SetIterateUpperBound([]byte("key3"))
+	for i := 0; i < 100; i++ {
+		it := db.NewIteratorWithBounds(ro, []byte("key1"), []byte("key2"))
+		it.SeekToFirst()
+		ensure.True(t, it.Valid())
+		it.Close()
+	}
+
+	expected := [][][]byte{
+		givenKeys[:1],
+		givenKeys[1:3],
+		givenKeys[2:],
+	}
+	for i, iter := range iters {
+		var actualKeys [][]byte
+		for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+			actualKeys = append(actualKeys, copyBytes(iter.Key().Data()))
+		}
+		ensure.Nil(t, iter.Err())
+		ensure.DeepEqual(t, actualKeys, expected[i])
+		iter.Close()
+	}
+	ro.Destroy()
+}
+
+func TestIteratorRefresh(t *testing.T) {
+	db := newTestDB(t, "TestIteratorRefresh", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key1"), []byte("val")))
+
+	ro := NewDefaultReadOptions()
+	iter := db.NewIterator(ro)
+
+	ensure.Nil(t, db.Put(wo, []byte("key2"), []byte("val")))
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	count := func() (n int) {
+		for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+			n++
+		}
+		ensure.Nil(t, iter.Err())
+		return
+	}
+	ensure.DeepEqual(t, count(), 1)
+
+	ensure.Nil(t, iter.Refresh())
+	ensure.DeepEqual(t, count(), 2)
+
+	iter.Close()
+	ensure.NotNil(t, iter.Refresh())
+}
+
+func TestIteratorManySearchKeysKeysOnlyAndValueFilter(t *testing.T) {
+	db := newTestDB(t, "TestIteratorManySearchKeysKeysOnlyAndValueFilter", nil)
+	defer db.Close()
+
+	// insert keys
+	givenKeys := [][]byte{[]byte("A1"), []byte("A2"), []byte("A3"), []byte("A4")}
+	givenValues := [][]byte{[]byte("red:1"), []byte("blue:2"), []byte("red:3"), []byte("red:4!")}
+	wo := NewDefaultWriteOptions()
+	for i, k := range givenKeys {
+		ensure.Nil(t, db.Put(wo, k, givenValues[i]))
+	}
+
+	ro := NewDefaultReadOptions()
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+
+	searches := make([]KeysSearch, 3)
+	searches[0] = KeysSearch{KeyFrom: []byte("A"), KeyPrefix: []byte("A"), Limit: 1000, KeysOnly: true}
+	searches[1] = KeysSearch{KeyFrom: []byte("A"), KeyPrefix: []byte("A"), Limit: 1000, ValuePrefix: []byte("red:")}
+	searches[2] = KeysSearch{KeyFrom: []byte("A"), KeyPrefix: []byte("A"), Limit: 1000, ValuePrefix: []byte("red:"), ValueSuffix: []byte("!"), KeysOnly: true}
+
+	manyManyKeys := iter.ManySearchKeys(searches)
+	defer manyManyKeys.Destroy()
+	result := manyManyKeys.Result()
+	ensure.DeepEqual(t, result[0].Keys(), givenKeys)
+	ensure.DeepEqual(t, result[0].Values(), [][]byte{nil, nil, nil, nil})
+	ensure.DeepEqual(t, result[1].Keys(), [][]byte{[]byte("A1"), []byte("A3"), []byte("A4")})
+	ensure.DeepEqual(t, result[1].Values(), [][]byte{[]byte("red:1"), []byte("red:3"), []byte("red:4!")})
+	ensure.DeepEqual(t, result[2].Keys(), [][]byte{[]byte("A4")})
+	ensure.DeepEqual(t, result[2].Values(), [][]byte{nil})
+	ensure.True(t, result[2].ResumeToken() == nil)
+}
+
+func TestIteratorManySearchKeysResumeToken(t *testing.T) {
+	db := newTestDB(t, "TestIteratorManySearchKeysResumeToken", nil)
+	defer db.Close()
+
+	// insert keys
+	givenKeys := [][]byte{[]byte("A1"), []byte("A2"), []byte("A3"), []byte("A4"), []byte("B1")}
+	wo := NewDefaultWriteOptions()
+	for _, k := range givenKeys {
+		ensure.Nil(t, db.Put(wo, k, []byte("val_"+string(k))))
+	}
+
+	ro := NewDefaultReadOptions()
+	iter := db.NewIterator(ro)
+	defer iter.Close()
+
+	// page through the A keys, by limit then by bytes budget
+	var pages [][][]byte
+	search := KeysSearch{KeyFrom: []byte("A"), KeyPrefix: []byte("A"), Limit: 2}
+	for {
+		manyManyKeys := iter.ManySearchKeys([]KeysSearch{search})
+		result := manyManyKeys.Result()[0]
+		var page [][]byte
+		for _, k := range result.Keys() {
+			page = append(page, copyBytes(k))
+		}
+		pages = append(pages, page)
+		token := result.ResumeToken()
+		manyManyKeys.Destroy()
+		if token == nil {
+			break
+		}
+		search.KeyFrom = token
+		search.Limit = 0
+		// each key and value is 2+6 bytes
+		search.MaxBytes = 10
+	}
+	ensure.DeepEqual(t, pages, [][][]byte{
+		{[]byte("A1"), []byte("A2")},
+		{[]byte("A3")},
+		{[]byte("A4")},
+	})
+}
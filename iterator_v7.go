@@ -0,0 +1,18 @@
+//go:build v7
+// +build v7
+
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// Timestamp returns the user-defined timestamp of the entry the iterator
+// currently holds, or nil if the column family doesn't use them.
+func (iter *Iterator) Timestamp() *Slice {
+	var cLen C.size_t
+	cTs := C.rocksdb_iter_timestamp(iter.c, &cLen)
+	if cTs == nil || cLen == 0 {
+		return nil
+	}
+	return &Slice{cTs, cLen, true}
+}
@@ -0,0 +1,80 @@
+package keys
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestPackRoundTrip(t *testing.T) {
+	tuple := []interface{}{
+		nil, []byte("a\x00b"), "tenant", int64(0), int64(-1), int64(math.MinInt64), int64(math.MaxInt64),
+		uint64(math.MaxUint64), float32(-1.5), 2.25, true, false, []byte{},
+		Desc("x\x00"), Desc(int64(-300)), Desc(3.5), Desc(true), Desc([]byte{0xff, 0x00}),
+	}
+	key, err := Pack(tuple...)
+	ensure.Nil(t, err)
+	decoded, err := Unpack(key)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, decoded, tuple)
+
+	// other integer types are decoded as int64
+	decoded, err = Unpack(MustPack(7, int8(-7), uint16(7)))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, decoded, []interface{}{int64(7), int64(-7), int64(7)})
+
+	_, err = Pack(struct{}{})
+	ensure.NotNil(t, err)
+	_, err = Pack(Desc(nil))
+	ensure.NotNil(t, err)
+	_, err = Unpack([]byte{stringCode, 'a'})
+	ensure.NotNil(t, err)
+}
+
+func TestPackOrder(t *testing.T) {
+	// each list is in ascending order
+	for _, sorted := range [][]interface{}{
+		{nil, []byte{}, []byte{0}, []byte("a"), "", "a", "a\x00", "a\x00\x00", "a\x01", "ab", "b",
+			int64(math.MinInt64), -256, -255, -1, 0, 1, 255, 256, int64(math.MaxInt64), uint64(math.MaxUint64),
+			float32(math.Inf(-1)), float32(-1), float32(0), float32(1), math.Inf(-1), -2.5, -0.5, 0.0, 0.5, 2.5, false, true},
+		{Desc(true), Desc(false), Desc(1.5), Desc(-1.5), Desc(100), Desc(1), Desc(-1), Desc(-100),
+			Desc("b"), Desc("ab"), Desc("a\x01"), Desc("a\x00\x00"), Desc("a\x00"), Desc("a"), Desc("")},
+	} {
+		for i := 1; i < len(sorted); i++ {
+			a, b := MustPack(sorted[i-1]), MustPack(sorted[i])
+			ensure.True(t, bytes.Compare(a, b) < 0, sorted[i-1], sorted[i])
+		}
+	}
+
+	// tuples sort element by element, shorter tuples first
+	ensure.True(t, bytes.Compare(MustPack("a"), MustPack("a", 1)) < 0)
+	ensure.True(t, bytes.Compare(MustPack("a", 2), MustPack("a\x00")) < 0)
+	ensure.True(t, bytes.Compare(MustPack(Desc("a"), 2), MustPack(Desc("a"))) > 0)
+	ensure.True(t, bytes.Compare(MustPack(Desc("a\x00")), MustPack(Desc("a"), 2)) < 0)
+	ensure.True(t, bytes.Compare(MustPack("t", Desc(int64(20)), 1), MustPack("t", Desc(int64(10)), 0)) < 0)
+}
+
+func TestPrefix(t *testing.T) {
+	prefix := MustPack("tenant", "orders")
+	start, limit := Range(prefix)
+	for _, key := range [][]byte{prefix, MustPack("tenant", "orders", 1), MustPack("tenant", "orders", Desc("x"))} {
+		ensure.True(t, bytes.Compare(start, key) <= 0 && bytes.Compare(key, limit) < 0, key)
+		ensure.True(t, HasPrefix(key, prefix), key)
+	}
+	for _, key := range [][]byte{MustPack("tenant", "orders\x00"), MustPack("tenant", "ordersx"), MustPack("tenant")} {
+		ensure.False(t, bytes.Compare(start, key) <= 0 && bytes.Compare(key, limit) < 0, key)
+		ensure.False(t, HasPrefix(key, prefix), key)
+	}
+
+	ensure.DeepEqual(t, PrefixEnd([]byte("ab")), []byte("ac"))
+	ensure.DeepEqual(t, PrefixEnd([]byte{'a', 0xff}), []byte("b"))
+	ensure.True(t, PrefixEnd([]byte{0xff, 0xff}) == nil)
+
+	n, err := PrefixLen(MustPack("abc", 1, 2), 2)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, len(MustPack("abc", 1)))
+	_, err = PrefixLen(MustPack("abc"), 2)
+	ensure.NotNil(t, err)
+}
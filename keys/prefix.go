@@ -0,0 +1,38 @@
+package keys
+
+import "bytes"
+
+// PrefixEnd returns the smallest key greater than all the keys starting with
+// the prefix, to be used as an exclusive upper bound such as with
+// ReadOptions.SetIterateUpperBound. It returns nil if there is no such key,
+// when the prefix is only made of 0xff bytes.
+func PrefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// Range returns the bounds of the keys of the tuples starting with the
+// packed tuple prefix, including the prefix itself. The limit is exclusive.
+//
+// Since a string or byte string is a byte prefix of the same string followed
+// by zeros, the keys of such tuples are excluded by the limit, which is
+// tighter than PrefixEnd(prefix).
+func Range(prefix []byte) (start, limit []byte) {
+	start = append([]byte{}, prefix...)
+	limit = append(append([]byte{}, prefix...), escape)
+	return start, limit
+}
+
+// HasPrefix reports whether the key encodes a tuple starting with the packed
+// tuple prefix. Unlike bytes.HasPrefix or Iterator.ValidForPrefix, it is false
+// when the last string or byte string of the prefix is only a prefix of the
+// one of the key.
+func HasPrefix(key, prefix []byte) bool {
+	return bytes.HasPrefix(key, prefix) && (len(key) == len(prefix) || key[len(prefix)] != escape)
+}
@@ -0,0 +1,361 @@
+// Package keys encodes tuples of values into keys whose bytewise order is
+// the order of the tuples, so that composite keys such as
+// (tenant, type, id, timestamp) can be used with the default comparator.
+//
+// The encoding is the one of the FoundationDB tuple layer for nil, byte
+// strings, strings, integers, floats and booleans. Elements wrapped by Desc
+// are encoded with all their bits inverted, so that they sort in descending
+// order.
+package keys
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Type codes of the encoded elements. The codes of the descending elements
+// are their inverse.
+const (
+	nilCode     = 0x00
+	bytesCode   = 0x01
+	stringCode  = 0x02
+	intZeroCode = 0x14
+	float32Code = 0x20
+	float64Code = 0x21
+	falseCode   = 0x26
+	trueCode    = 0x27
+
+	// byte strings are terminated by terminator, after escaping their
+	// zeros with escape.
+	terminator = 0x00
+	escape     = 0xff
+	// descTerminator ends the byte strings of descending elements, which
+	// must sort before their escaped zeros once inverted.
+	descTerminator = 0x01
+)
+
+// Descending wraps an element of a tuple sorting in descending order.
+type Descending struct {
+	Value interface{}
+}
+
+// Desc returns the element v sorting in descending order. v can't be nil.
+func Desc(v interface{}) Descending {
+	return Descending{v}
+}
+
+// Pack encodes the elements of a tuple, which can be nil, []byte, string,
+// signed or unsigned integers, float32, float64, bool or Descending.
+func Pack(elems ...interface{}) ([]byte, error) {
+	return AppendPack(nil, elems...)
+}
+
+// MustPack is like Pack but panics if an element can't be encoded.
+func MustPack(elems ...interface{}) []byte {
+	key, err := Pack(elems...)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// AppendPack appends the encoded elements of a tuple to dst.
+func AppendPack(dst []byte, elems ...interface{}) ([]byte, error) {
+	for i, elem := range elems {
+		var err error
+		if desc, ok := elem.(Descending); ok {
+			dst, err = appendDesc(dst, desc.Value)
+		} else {
+			dst, err = appendElem(dst, elem, terminator)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("keys: element %d: %v", i, err)
+		}
+	}
+	return dst, nil
+}
+
+func appendDesc(dst []byte, v interface{}) ([]byte, error) {
+	switch v.(type) {
+	case nil:
+		return nil, fmt.Errorf("nil can't be descending")
+	case Descending:
+		return nil, fmt.Errorf("nested Descending")
+	}
+	start := len(dst)
+	dst, err := appendElem(dst, v, descTerminator)
+	if err != nil {
+		return nil, err
+	}
+	for i := start; i < len(dst); i++ {
+		dst[i] = ^dst[i]
+	}
+	return dst, nil
+}
+
+func appendElem(dst []byte, v interface{}, term byte) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, nilCode), nil
+	case []byte:
+		return appendBytes(dst, bytesCode, v, term), nil
+	case string:
+		return appendBytes(dst, stringCode, []byte(v), term), nil
+	case bool:
+		if v {
+			return append(dst, trueCode), nil
+		}
+		return append(dst, falseCode), nil
+	case float32:
+		bits := math.Float32bits(v)
+		if bits&(1<<31) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 31
+		}
+		dst = append(dst, float32Code)
+		return append(dst, byte(bits>>24), byte(bits>>16), byte(bits>>8), byte(bits)), nil
+	case float64:
+		bits := math.Float64bits(v)
+		if bits&(1<<63) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		dst = append(dst, float64Code)
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], bits)
+		return append(dst, buf[:]...), nil
+	case int:
+		return appendInt(dst, int64(v)), nil
+	case int8:
+		return appendInt(dst, int64(v)), nil
+	case int16:
+		return appendInt(dst, int64(v)), nil
+	case int32:
+		return appendInt(dst, int64(v)), nil
+	case int64:
+		return appendInt(dst, v), nil
+	case uint:
+		return appendUint(dst, uint64(v)), nil
+	case uint8:
+		return appendUint(dst, uint64(v)), nil
+	case uint16:
+		return appendUint(dst, uint64(v)), nil
+	case uint32:
+		return appendUint(dst, uint64(v)), nil
+	case uint64:
+		return appendUint(dst, v), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", v)
+}
+
+func appendBytes(dst []byte, code byte, b []byte, term byte) []byte {
+	dst = append(dst, code)
+	for _, c := range b {
+		dst = append(dst, c)
+		if c == 0x00 {
+			dst = append(dst, escape)
+		}
+	}
+	if term != terminator {
+		return append(dst, terminator, term)
+	}
+	return append(dst, terminator)
+}
+
+// intSize returns the number of bytes needed by the magnitude of an integer.
+func intSize(u uint64) int {
+	n := 0
+	for ; u != 0; u >>= 8 {
+		n++
+	}
+	return n
+}
+
+func appendUint(dst []byte, u uint64) []byte {
+	n := intSize(u)
+	dst = append(dst, byte(intZeroCode+n))
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(u>>(8*uint(i))))
+	}
+	return dst
+}
+
+func appendInt(dst []byte, v int64) []byte {
+	if v >= 0 {
+		return appendUint(dst, uint64(v))
+	}
+	// negative integers are encoded as the one's complement of their
+	// magnitude, so that greater magnitudes sort first.
+	u := uint64(-v)
+	n := intSize(u)
+	dst = append(dst, byte(intZeroCode-n))
+	u = ^u
+	for i := n - 1; i >= 0; i-- {
+		dst = append(dst, byte(u>>(8*uint(i))))
+	}
+	return dst
+}
+
+// Unpack decodes the elements of a tuple encoded by Pack. Integers are
+// decoded as int64, or uint64 if they don't fit, and descending elements as
+// Descending.
+func Unpack(key []byte) ([]interface{}, error) {
+	var elems []interface{}
+	for len(key) > 0 {
+		elem, n, err := decodeElem(key)
+		if err != nil {
+			return nil, fmt.Errorf("keys: element %d: %v", len(elems), err)
+		}
+		elems = append(elems, elem)
+		key = key[n:]
+	}
+	return elems, nil
+}
+
+// PrefixLen returns the size of the first n encoded elements of the key.
+// Keys whose first n elements have a fixed size, such as fixed-length
+// strings, can use it with NewFixedPrefixTransform.
+func PrefixLen(key []byte, n int) (int, error) {
+	size := 0
+	for i := 0; i < n; i++ {
+		_, m, err := decodeElem(key[size:])
+		if err != nil {
+			return 0, fmt.Errorf("keys: element %d: %v", i, err)
+		}
+		size += m
+	}
+	return size, nil
+}
+
+// decodeElem decodes the first element of b, returning its encoded size.
+func decodeElem(b []byte) (interface{}, int, error) {
+	if len(b) == 0 {
+		return nil, 0, fmt.Errorf("missing element")
+	}
+	if b[0] < 0x80 {
+		return decodeAsc(b, terminator)
+	}
+
+	// descending elements are inverted: decode a copy inverted back, whose
+	// byte strings have the longer descending terminator.
+	inv := make([]byte, len(b))
+	for i, c := range b {
+		inv[i] = ^c
+	}
+	v, n, err := decodeAsc(inv, descTerminator)
+	if err != nil {
+		return nil, 0, err
+	}
+	return Descending{v}, n, nil
+}
+
+func decodeAsc(b []byte, term byte) (interface{}, int, error) {
+	code := b[0]
+	switch {
+	case code == nilCode:
+		return nil, 1, nil
+	case code == bytesCode || code == stringCode:
+		s, n, err := decodeBytes(b[1:], term)
+		if err != nil {
+			return nil, 0, err
+		}
+		if code == stringCode {
+			return string(s), n + 1, nil
+		}
+		return s, n + 1, nil
+	case code == falseCode:
+		return false, 1, nil
+	case code == trueCode:
+		return true, 1, nil
+	case code == float32Code:
+		if len(b) < 5 {
+			return nil, 0, fmt.Errorf("truncated float32")
+		}
+		bits := binary.BigEndian.Uint32(b[1:])
+		if bits&(1<<31) != 0 {
+			bits &^= 1 << 31
+		} else {
+			bits = ^bits
+		}
+		return math.Float32frombits(bits), 5, nil
+	case code == float64Code:
+		if len(b) < 9 {
+			return nil, 0, fmt.Errorf("truncated float64")
+		}
+		bits := binary.BigEndian.Uint64(b[1:])
+		if bits&(1<<63) != 0 {
+			bits &^= 1 << 63
+		} else {
+			bits = ^bits
+		}
+		return math.Float64frombits(bits), 9, nil
+	case code >= intZeroCode-8 && code <= intZeroCode+8:
+		return decodeInt(b)
+	}
+	return nil, 0, fmt.Errorf("unknown type code %#x", code)
+}
+
+func decodeBytes(b []byte, term byte) ([]byte, int, error) {
+	var s []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] != 0x00 {
+			s = append(s, b[i])
+			continue
+		}
+		if i+1 < len(b) && b[i+1] == escape {
+			s = append(s, 0x00)
+			i++
+			continue
+		}
+		if term == terminator {
+			return nonNil(s), i + 1, nil
+		}
+		if i+1 < len(b) && b[i+1] == term {
+			return nonNil(s), i + 2, nil
+		}
+		return nil, 0, fmt.Errorf("invalid byte string terminator")
+	}
+	return nil, 0, fmt.Errorf("unterminated byte string")
+}
+
+func nonNil(s []byte) []byte {
+	if s == nil {
+		return []byte{}
+	}
+	return s
+}
+
+func decodeInt(b []byte) (interface{}, int, error) {
+	code := int(b[0])
+	n := code - intZeroCode
+	neg := n < 0
+	if neg {
+		n = -n
+	}
+	if len(b) < n+1 {
+		return nil, 0, fmt.Errorf("truncated integer")
+	}
+	var u uint64
+	for _, c := range b[1 : n+1] {
+		u = u<<8 | uint64(c)
+	}
+	if !neg {
+		if u > math.MaxInt64 {
+			return u, n + 1, nil
+		}
+		return int64(u), n + 1, nil
+	}
+	// undo the one's complement on n bytes
+	if n < 8 {
+		u ^= 1<<(8*uint(n)) - 1
+	} else {
+		u = ^u
+	}
+	if u > 1<<63 {
+		return nil, 0, fmt.Errorf("integer overflows int64")
+	}
+	return -int64(u), n + 1, nil
+}
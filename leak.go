@@ -0,0 +1,195 @@
+package gorocksdb
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// LeakStats holds the leak tracking counters of one handle type.
+type LeakStats struct {
+	// Allocated is the number of handles created while tracking was enabled.
+	Allocated uint64
+	// Destroyed is the number of tracked handles released explicitly.
+	Destroyed uint64
+	// Leaked is the number of tracked handles garbage collected without
+	// being released.
+	Leaked uint64
+}
+
+// Live returns the number of tracked handles neither released nor collected yet.
+func (s LeakStats) Live() uint64 {
+	return s.Allocated - s.Destroyed - s.Leaked
+}
+
+// Leak describes a handle that was garbage collected without being released.
+type Leak struct {
+	// Type is the name of the handle type, e.g. "ReadOptions".
+	Type string
+	// Stack is the goroutine stack that allocated the handle.
+	Stack string
+}
+
+func (l Leak) String() string {
+	return fmt.Sprintf("gorocksdb: %s garbage collected without being released, allocated at:\n%s", l.Type, l.Stack)
+}
+
+var leakTracker = struct {
+	enabled int32
+	tracked int64
+
+	mu      sync.Mutex
+	live    map[uintptr]string
+	stats   map[string]*LeakStats
+	handler func(Leak)
+}{
+	live:  make(map[uintptr]string),
+	stats: make(map[string]*LeakStats),
+	handler: func(leak Leak) {
+		fmt.Fprintln(os.Stderr, leak)
+	},
+}
+
+// SetLeakTracking enables or disables leak tracking.
+//
+// While enabled, every handle backed by C memory (Options, ReadOptions,
+// Iterator, Slice, WriteBatch, Cache, Snapshot, ...) records its allocation
+// site and gets a finalizer reporting it to the leak handler if it is garbage
+// collected before Destroy, Free, Close or ReleaseSnapshot was called.
+// Handles allocated while tracking was disabled are never reported.
+//
+// Tracking costs a stack capture per allocation, so it is meant for tests
+// and debugging. Building with the "leaktrack" tag enables it at startup.
+func SetLeakTracking(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&leakTracker.enabled, v)
+}
+
+// LeakTrackingEnabled reports whether leak tracking is enabled.
+func LeakTrackingEnabled() bool {
+	return atomic.LoadInt32(&leakTracker.enabled) != 0
+}
+
+// SetLeakHandler sets the function called for every leaked handle.
+// The default handler prints the leak to stderr; nil disables reporting.
+// The handler runs on the finalizer goroutine and must not block.
+func SetLeakHandler(fn func(Leak)) {
+	leakTracker.mu.Lock()
+	leakTracker.handler = fn
+	leakTracker.mu.Unlock()
+}
+
+// GetLeakStats returns the leak tracking counters keyed by handle type.
+func GetLeakStats() map[string]LeakStats {
+	leakTracker.mu.Lock()
+	defer leakTracker.mu.Unlock()
+	stats := make(map[string]LeakStats, len(leakTracker.stats))
+	for typ, s := range leakTracker.stats {
+		stats[typ] = *s
+	}
+	return stats
+}
+
+// LiveHandles returns the number of tracked handles neither released nor
+// collected yet, summed over all handle types.
+func LiveHandles() (n uint64) {
+	for _, s := range GetLeakStats() {
+		n += s.Live()
+	}
+	return
+}
+
+// LiveHandleTypes returns the sorted names of the handle types having live
+// tracked handles.
+func LiveHandleTypes() (types []string) {
+	for typ, s := range GetLeakStats() {
+		if s.Live() > 0 {
+			types = append(types, typ)
+		}
+	}
+	sort.Strings(types)
+	return
+}
+
+// ResetLeakStats clears all counters. Handles still tracked keep being
+// tracked but are no longer counted as allocated.
+func ResetLeakStats() {
+	leakTracker.mu.Lock()
+	defer leakTracker.mu.Unlock()
+	for typ := range leakTracker.stats {
+		leakTracker.stats[typ] = &LeakStats{}
+	}
+	for _, typ := range leakTracker.live {
+		// keep Live() from underflowing once those handles are released
+		leakTracker.stats[typ].Allocated++
+	}
+}
+
+func leakStatsOf(typ string) *LeakStats {
+	s, ok := leakTracker.stats[typ]
+	if !ok {
+		s = &LeakStats{}
+		leakTracker.stats[typ] = s
+	}
+	return s
+}
+
+// trackAlloc starts tracking a newly allocated handle, which must be a pointer.
+func trackAlloc(typ string, obj interface{}) {
+	if !LeakTrackingEnabled() {
+		return
+	}
+	stack := callerStack(2)
+	p := reflect.ValueOf(obj).Pointer()
+
+	leakTracker.mu.Lock()
+	leakTracker.live[p] = typ
+	leakStatsOf(typ).Allocated++
+	leakTracker.mu.Unlock()
+	atomic.AddInt64(&leakTracker.tracked, 1)
+
+	runtime.SetFinalizer(obj, func(obj interface{}) {
+		leakTracker.mu.Lock()
+		if _, ok := leakTracker.live[p]; !ok {
+			leakTracker.mu.Unlock()
+			return
+		}
+		delete(leakTracker.live, p)
+		leakStatsOf(typ).Leaked++
+		handler := leakTracker.handler
+		leakTracker.mu.Unlock()
+		atomic.AddInt64(&leakTracker.tracked, -1)
+
+		if handler != nil {
+			handler(Leak{Type: typ, Stack: stack})
+		}
+	})
+}
+
+// trackFree stops tracking a released handle.
+func trackFree(obj interface{}) {
+	if atomic.LoadInt64(&leakTracker.tracked) == 0 {
+		return
+	}
+	p := reflect.ValueOf(obj).Pointer()
+
+	leakTracker.mu.Lock()
+	typ, ok := leakTracker.live[p]
+	if ok {
+		delete(leakTracker.live, p)
+		leakStatsOf(typ).Destroyed++
+	}
+	leakTracker.mu.Unlock()
+
+	if ok {
+		atomic.AddInt64(&leakTracker.tracked, -1)
+		runtime.SetFinalizer(obj, nil)
+	}
+}
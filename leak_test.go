@@ -0,0 +1,46 @@
+package gorocksdb
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestLeakTracking(t *testing.T) {
+	enabled := LeakTrackingEnabled()
+	defer SetLeakTracking(enabled)
+	SetLeakTracking(true)
+
+	leaks := make(chan Leak, 16)
+	SetLeakHandler(func(leak Leak) { leaks <- leak })
+	defer SetLeakHandler(nil)
+	ResetLeakStats()
+
+	ro := NewDefaultReadOptions()
+	_ = NewDefaultWriteOptions()
+	ensure.DeepEqual(t, GetLeakStats()["ReadOptions"].Live(), uint64(1))
+	ensure.DeepEqual(t, GetLeakStats()["WriteOptions"].Live(), uint64(1))
+
+	ro.Destroy()
+	ro.Destroy()
+	ensure.DeepEqual(t, GetLeakStats()["ReadOptions"], LeakStats{Allocated: 1, Destroyed: 1})
+
+	var leak Leak
+	deadline := time.Now().Add(5 * time.Second)
+	for leak.Type == "" {
+		if time.Now().After(deadline) {
+			t.Fatal("the leaked WriteOptions were not reported")
+		}
+		runtime.GC()
+		select {
+		case leak = <-leaks:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	ensure.DeepEqual(t, leak.Type, "WriteOptions")
+	ensure.StringContains(t, leak.Stack, "TestLeakTracking")
+	ensure.DeepEqual(t, GetLeakStats()["WriteOptions"], LeakStats{Allocated: 1, Leaked: 1})
+	ensure.DeepEqual(t, LiveHandles(), uint64(0))
+}
@@ -0,0 +1,8 @@
+//go:build leaktrack
+// +build leaktrack
+
+package gorocksdb
+
+func init() {
+	SetLeakTracking(true)
+}
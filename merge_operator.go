@@ -2,6 +2,12 @@ package gorocksdb
 
 // #include "rocksdb/c.h"
 import "C"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
 
 // A MergeOperator specifies the SEMANTICS of a merge, which only
 // client knows. It could be numeric addition, list append, string
@@ -47,6 +53,157 @@ type MergeOperator interface {
 	Name() string
 }
 
+// A MultiMerger is a MergeOperator able to combine several operands at once,
+// rather than pairwise with PartialMerge.
+type MultiMerger interface {
+	// PartialMergeMulti combines at least two operands, front() first,
+	// into a single merge operation. It returns false if this is
+	// impossible or infeasible, in which case the library keeps the
+	// operands and applies them once a base value is seen.
+	//
+	// The RocksDB C API doesn't expose AllowSingleOperand, so this is never
+	// called with a single operand.
+	PartialMergeMulti(key []byte, operands [][]byte) ([]byte, bool)
+}
+
+// A FallibleMerger is a MergeOperator able to tell why a full merge fails.
+// The error is returned, wrapped in a MergeError, by the DB operation that
+// triggered the merge, see MergeError.
+type FallibleMerger interface {
+	// TryFullMerge is like FullMerge, returning an error on failure.
+	// It is called instead of FullMerge.
+	TryFullMerge(key, existingValue []byte, operands [][]byte) ([]byte, error)
+}
+
+// MergeError is the error returned by a DB operation whose merge failed in
+// a Go MergeOperator, rather than the generic corruption status RocksDB
+// reports.
+//
+// Only the single key operations of DB return it: Get, GetBytes, GetCF,
+// GetBytesCF, GetPinned, Merge and MergeCF. The other operations, such as MultiGet,
+// iterators, batch writes and transactions, can't tell which of their keys
+// failed and return the status of RocksDB.
+type MergeError struct {
+	// Operator is the name of the merge operator.
+	Operator string
+	// Key is the key whose merge failed.
+	Key []byte
+	// Err is the error returned by a FallibleMerger, or a generic error if
+	// FullMerge returned false.
+	Err error
+}
+
+func (e *MergeError) Error() string {
+	return fmt.Sprintf("merge operator %s failed to merge key %q: %v", e.Operator, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *MergeError) Unwrap() error { return e.Err }
+
+// errMergeFailed is the error of a MergeError when FullMerge returns false.
+var errMergeFailed = errors.New("FullMerge returned false")
+
+// maxMergeFailures bounds the number of merge failures a merge operator
+// remembers until the operations that triggered them return.
+const maxMergeFailures = 1024
+
+// mergeFailureLog remembers the recent merge failures of a merge operator by
+// key, so that they can be returned by the operations that triggered them.
+type mergeFailureLog struct {
+	mu    sync.Mutex
+	byKey map[string]*MergeError
+}
+
+func (l *mergeFailureLog) record(e *MergeError) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.byKey == nil || len(l.byKey) >= maxMergeFailures {
+		// failures triggered by compactions are never taken
+		l.byKey = make(map[string]*MergeError)
+	}
+	l.byKey[string(e.Key)] = e
+}
+
+// take returns and forgets the last merge failure of the key.
+func (l *mergeFailureLog) take(key []byte) *MergeError {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.byKey[string(key)]
+	delete(l.byKey, string(key))
+	return e
+}
+
+// mergeFailureLogs holds the failure logs of the Go merge operators of the
+// column families of a database.
+type mergeFailureLogs struct {
+	mu   sync.RWMutex
+	logs []*mergeFailureLog
+}
+
+func newMergeFailureLogs(opts ...*Options) *mergeFailureLogs {
+	m := &mergeFailureLogs{}
+	m.add(opts...)
+	return m
+}
+
+// add adds the failure logs of the merge operators of the options.
+func (m *mergeFailureLogs) add(opts ...*Options) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, o := range opts {
+		if o == nil || o.mergeFailures == nil {
+			continue
+		}
+		known := false
+		for _, log := range m.logs {
+			known = known || log == o.mergeFailures
+		}
+		if !known {
+			m.logs = append(m.logs, o.mergeFailures)
+		}
+	}
+}
+
+func (m *mergeFailureLogs) take(key []byte) *MergeError {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, log := range m.logs {
+		if e := log.take(key); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// mergeAwareError converts the error of a DB operation on the key, returning
+// the MergeError reported by a Go merge operator of the database if the
+// operation failed to merge the key. RocksDB only reports the failure by its
+// status message, which depends on its version.
+func (db *DB) mergeAwareError(cErr *C.char, key []byte) error {
+	msg := C.GoString(cErr)
+	for _, status := range mergeFailureStatuses {
+		if !strings.Contains(msg, status) {
+			continue
+		}
+		if e := db.merges.take(key); e != nil {
+			return e
+		}
+	}
+	return errors.New(msg)
+}
+
+// mergeFailureStatuses are the messages of the statuses RocksDB reports a
+// failed merge with: "Could not perform merge" before RocksDB added the
+// kMergeOperatorFailed subcode, "Merge operator failed" since. They are
+// pinned by the tests of each build tag.
+var mergeFailureStatuses = []string{"Could not perform merge", "Merge operator failed"}
+
 // NewNativeMergeOperator creates a MergeOperator object.
 func NewNativeMergeOperator(c *C.rocksdb_mergeoperator_t) MergeOperator {
 	return nativeMergeOperator{c}
@@ -70,10 +227,12 @@ var mergeOperators = NewCOWList()
 type mergeOperatorWrapper struct {
 	name          *C.char
 	mergeOperator MergeOperator
+	failures      *mergeFailureLog
 }
 
-func registerMergeOperator(merger MergeOperator) int {
-	return mergeOperators.Append(mergeOperatorWrapper{C.CString(merger.Name()), merger})
+func registerMergeOperator(merger MergeOperator) (int, *mergeFailureLog) {
+	failures := &mergeFailureLog{}
+	return mergeOperators.Append(mergeOperatorWrapper{C.CString(merger.Name()), merger, failures}), failures
 }
 
 //export gorocksdb_mergeoperator_full_merge
@@ -87,7 +246,21 @@ func gorocksdb_mergeoperator_full_merge(idx int, cKey *C.char, cKeyLen C.size_t,
 		operands[i] = charToByte(rawOperands[i], len)
 	}
 
-	newValue, success := mergeOperators.Get(idx).(mergeOperatorWrapper).mergeOperator.FullMerge(key, existingValue, operands)
+	wrapper := mergeOperators.Get(idx).(mergeOperatorWrapper)
+	merger := wrapper.mergeOperator
+	var (
+		newValue []byte
+		success  bool
+	)
+	if fm, ok := merger.(FallibleMerger); ok {
+		var err error
+		if newValue, err = fm.TryFullMerge(key, existingValue, operands); err != nil {
+			wrapper.failures.record(&MergeError{Operator: merger.Name(), Key: copyBytes(key), Err: err})
+		}
+		success = err == nil
+	} else if newValue, success = merger.FullMerge(key, existingValue, operands); !success {
+		wrapper.failures.record(&MergeError{Operator: merger.Name(), Key: copyBytes(key), Err: errMergeFailed})
+	}
 	newValueLen := len(newValue)
 
 	*cNewValueLen = C.size_t(newValueLen)
@@ -110,13 +283,17 @@ func gorocksdb_mergeoperator_partial_merge_multi(idx int, cKey *C.char, cKeyLen
 	success := true
 
 	merger := mergeOperators.Get(idx).(mergeOperatorWrapper).mergeOperator
-	leftOperand := operands[0]
-	for i := 1; i < int(cNumOperands); i++ {
-		newValue, success = merger.PartialMerge(key, leftOperand, operands[i])
-		if !success {
-			break
+	if mm, ok := merger.(MultiMerger); ok {
+		newValue, success = mm.PartialMergeMulti(key, operands)
+	} else {
+		leftOperand := operands[0]
+		for i := 1; i < int(cNumOperands); i++ {
+			newValue, success = merger.PartialMerge(key, leftOperand, operands[i])
+			if !success {
+				break
+			}
+			leftOperand = newValue
 		}
-		leftOperand = newValue
 	}
 
 	newValueLen := len(newValue)
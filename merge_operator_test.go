@@ -1,9 +1,12 @@
 package gorocksdb
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/facebookgo/ensure"
+	"github.com/flier/gorocksdb/mergeops"
 )
 
 func TestMergeOperator(t *testing.T) {
@@ -52,3 +55,142 @@ func (m *mockMergeOperator) FullMerge(key, existingValue []byte, operands [][]by
 func (m *mockMergeOperator) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
 	return m.partialMerge(key, leftOperand, rightOperand)
 }
+
+func TestDBIncrement(t *testing.T) {
+	db := newTestDB(t, "TestDBIncrement", func(opts *Options) {
+		opts.SetMergeOperator(mergeops.Int64Add{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	givenKey := []byte("counter")
+	ensure.Nil(t, db.Increment(wo, givenKey, 5))
+	ensure.Nil(t, db.Increment(wo, givenKey, 10))
+	ensure.Nil(t, db.Increment(wo, givenKey, -3))
+
+	v, err := db.GetBytes(ro, givenKey)
+	ensure.Nil(t, err)
+	counter, ok := mergeops.FixedEncoding.DecodeInt64(v)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, counter, int64(12))
+
+	// trigger a compaction to ensure that a merge is performed
+	db.CompactRange(Range{nil, nil})
+	ensure.Nil(t, db.Increment(wo, givenKey, -20))
+	v, err = db.GetBytes(ro, givenKey)
+	ensure.Nil(t, err)
+	counter, _ = mergeops.FixedEncoding.DecodeInt64(v)
+	ensure.DeepEqual(t, counter, int64(-8))
+}
+
+func TestMergeOperatorPartialMergeMulti(t *testing.T) {
+	merger := &mockMultiMerger{}
+	db := newTestDB(t, "TestMergeOperatorPartialMergeMulti", func(opts *Options) {
+		opts.SetMergeOperator(merger)
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	for _, operand := range []string{"a", "b", "c"} {
+		ensure.Nil(t, db.Merge(wo, []byte("key"), []byte(operand)))
+	}
+	// flushing combines the operands without a base value
+	ensure.Nil(t, db.Flush(NewDefaultFlushOptions()))
+
+	v, err := db.GetBytes(NewDefaultReadOptions(), []byte("key"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("abc"))
+	for _, n := range merger.partialMerges {
+		ensure.True(t, n >= 2)
+	}
+}
+
+func TestMergeOperatorError(t *testing.T) {
+	db := newTestDB(t, "TestMergeOperatorError", func(opts *Options) {
+		opts.SetMergeOperator(&mockMultiMerger{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("base")))
+	ensure.Nil(t, db.Merge(wo, []byte("key"), []byte("!")))
+
+	_, err := db.Get(NewDefaultReadOptions(), []byte("key"))
+	mergeErr, ok := err.(*MergeError)
+	ensure.True(t, ok, err)
+	ensure.DeepEqual(t, mergeErr.Operator, "gorocksdb.multi")
+	ensure.DeepEqual(t, mergeErr.Key, []byte("key"))
+	ensure.DeepEqual(t, mergeErr.Err.Error(), `invalid operand "!"`)
+
+	// the failure was taken by the Get, and a database with another merge
+	// operator doesn't see the failures of this one
+	other := newTestDB(t, "TestMergeOperatorErrorOther", func(opts *Options) {
+		opts.SetMergeOperator(&mockMultiMerger{})
+	})
+	defer other.Close()
+	ensure.Nil(t, other.Put(wo, []byte("key"), []byte("base")))
+	_, err = db.Get(NewDefaultReadOptions(), []byte("key"))
+	_, ok = err.(*MergeError)
+	ensure.True(t, ok, err)
+	ensure.Nil(t, db.merges.take([]byte("key")))
+	ensure.Nil(t, other.merges.take([]byte("key")))
+
+	// a batch write can't tell which key failed
+	batch := NewWriteBatch()
+	defer batch.Destroy()
+	batch.Merge([]byte("key"), []byte("!"))
+	if err := db.Write(wo, batch); err != nil {
+		_, ok = err.(*MergeError)
+		ensure.False(t, ok)
+	}
+}
+
+// mockMultiMerger concatenates its operands, failing on "!".
+type mockMultiMerger struct {
+	partialMerges []int
+}
+
+func (m *mockMultiMerger) Name() string { return "gorocksdb.multi" }
+func (m *mockMultiMerger) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	v, err := m.TryFullMerge(key, existingValue, operands)
+	return v, err == nil
+}
+func (m *mockMultiMerger) TryFullMerge(key, existingValue []byte, operands [][]byte) ([]byte, error) {
+	v := append([]byte{}, existingValue...)
+	for _, operand := range operands {
+		if string(operand) == "!" {
+			return nil, fmt.Errorf("invalid operand %q", operand)
+		}
+		v = append(v, operand...)
+	}
+	return v, nil
+}
+func (m *mockMultiMerger) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return nil, false
+}
+func (m *mockMultiMerger) PartialMergeMulti(key []byte, operands [][]byte) ([]byte, bool) {
+	m.partialMerges = append(m.partialMerges, len(operands))
+	return bytes.Join(operands, nil), true
+}
+
+// mergeFailureStatus returns the message of the status RocksDB reports a
+// failed merge with, as returned by the operations that don't return a
+// MergeError.
+func mergeFailureStatus(t *testing.T) string {
+	db := newTestDB(t, "TestMergeFailureStatus", func(opts *Options) {
+		opts.SetMergeOperator(&mockMultiMerger{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("base")))
+	ensure.Nil(t, db.Merge(wo, []byte("key"), []byte("!")))
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	_, err := db.MultiGet(ro, []byte("key"))
+	ensure.NotNil(t, err)
+	return err.Error()
+}
@@ -0,0 +1,16 @@
+//go:build !v6
+// +build !v6
+
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestMergeFailureStatus(t *testing.T) {
+	// RocksDB 5 reports the failed merges with a Corruption status without
+	// subcode
+	ensure.StringContains(t, mergeFailureStatus(t), "Could not perform merge")
+}
@@ -0,0 +1,21 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeFailureStatus(t *testing.T) {
+	// the message changed with the kMergeOperatorFailed subcode, within the
+	// releases the v6 tag covers
+	status := mergeFailureStatus(t)
+	for _, s := range mergeFailureStatuses {
+		if strings.Contains(status, s) {
+			return
+		}
+	}
+	t.Fatalf("merge failure status %q matches none of %q", status, mergeFailureStatuses)
+}
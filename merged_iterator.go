@@ -0,0 +1,338 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"container/heap"
+)
+
+// DuplicateKeyPolicy tells a MergedIterator what to do with a key found in
+// several of its sources.
+type DuplicateKeyPolicy int
+
+const (
+	// KeepAllDuplicates yields the key once per source holding it, in
+	// source order.
+	KeepAllDuplicates = DuplicateKeyPolicy(0)
+	// FirstSourceWins only yields the key from the first source holding it.
+	FirstSourceWins = DuplicateKeyPolicy(1)
+	// LastSourceWins only yields the key from the last source holding it.
+	LastSourceWins = DuplicateKeyPolicy(2)
+)
+
+// MergedIterator iterates over several iterators at once, as if they were
+// a single one, yielding their keys in comparator order. Keys found in
+// several sources are resolved according to a DuplicateKeyPolicy, and
+// Source tells which iterator the current entry comes from.
+//
+// The iterators should read from a consistent view of the database, such as
+// the ones returned by DB.NewIterators or created with the same snapshot.
+type MergedIterator struct {
+	iters   []*Iterator
+	cfs     ColumnFamilyHandles
+	compare func(a, b []byte) int
+	policy  DuplicateKeyPolicy
+
+	// keys caches the current key of each valid iterator.
+	keys    [][]byte
+	heap    []int
+	reverse bool
+	cur     int
+}
+
+// NewMergedIterator creates a MergedIterator over the given iterators, which
+// it takes ownership of. compare must order the keys like the comparator of
+// the iterated column families, nil meaning bytes.Compare.
+func NewMergedIterator(iters []*Iterator, compare func(a, b []byte) int, policy DuplicateKeyPolicy) *MergedIterator {
+	if compare == nil {
+		compare = bytes.Compare
+	}
+	return &MergedIterator{
+		iters:   iters,
+		compare: compare,
+		policy:  policy,
+		keys:    make([][]byte, len(iters)),
+		heap:    make([]int, 0, len(iters)),
+		cur:     -1,
+	}
+}
+
+// NewMergedIterator creates a MergedIterator over the given column families,
+// reading from a consistent view of the database. SourceCF tells which column
+// family the current entry comes from. The iterators are created by
+// DB.NewIterators, so they can't be refreshed.
+func (db *DB) NewMergedIterator(opts *ReadOptions, cfs ColumnFamilyHandles, policy DuplicateKeyPolicy) (*MergedIterator, error) {
+	iters, err := db.NewIterators(opts, cfs)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMergedIterator(iters, nil, policy)
+	m.cfs = cfs
+	return m, nil
+}
+
+// Valid returns false only when the MergedIterator has iterated past either
+// the first or the last key of all its sources.
+func (m *MergedIterator) Valid() bool {
+	return m.cur >= 0
+}
+
+// SeekToFirst moves the iterator to the first key of all its sources.
+func (m *MergedIterator) SeekToFirst() {
+	for _, iter := range m.iters {
+		iter.SeekToFirst()
+	}
+	m.init(false)
+}
+
+// SeekToLast moves the iterator to the last key of all its sources.
+func (m *MergedIterator) SeekToLast() {
+	for _, iter := range m.iters {
+		iter.SeekToLast()
+	}
+	m.init(true)
+}
+
+// Seek moves the iterator to the first key greater than or equal to the key.
+func (m *MergedIterator) Seek(key []byte) {
+	for _, iter := range m.iters {
+		iter.Seek(key)
+	}
+	m.init(false)
+}
+
+// SeekForPrev moves the iterator to the last key less than or equal to the key.
+func (m *MergedIterator) SeekForPrev(key []byte) {
+	for _, iter := range m.iters {
+		iter.SeekForPrev(key)
+	}
+	m.init(true)
+}
+
+// Next moves the iterator to the next key. It does nothing if the iterator
+// is not valid.
+func (m *MergedIterator) Next() {
+	if !m.Valid() {
+		return
+	}
+	if m.reverse {
+		m.switchDirection(false)
+	} else {
+		m.step(m.cur)
+	}
+	m.resolve()
+}
+
+// Prev moves the iterator to the previous key. It does nothing if the
+// iterator is not valid.
+func (m *MergedIterator) Prev() {
+	if !m.Valid() {
+		return
+	}
+	if !m.reverse {
+		m.switchDirection(true)
+	} else {
+		m.step(m.cur)
+	}
+	m.resolve()
+}
+
+// Key returns the key the iterator currently holds.
+func (m *MergedIterator) Key() *Slice {
+	return m.iters[m.cur].Key()
+}
+
+// Value returns the value the iterator currently holds.
+func (m *MergedIterator) Value() *Slice {
+	return m.iters[m.cur].Value()
+}
+
+// Source returns the index of the iterator the current entry comes from.
+func (m *MergedIterator) Source() int {
+	return m.cur
+}
+
+// SourceCF returns the column family the current entry comes from, or nil if
+// the MergedIterator was not created by DB.NewMergedIterator.
+func (m *MergedIterator) SourceCF() *ColumnFamilyHandle {
+	if m.cfs == nil {
+		return nil
+	}
+	return m.cfs[m.cur]
+}
+
+// Err returns the first error of the sources, if any.
+func (m *MergedIterator) Err() error {
+	for _, iter := range m.iters {
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the sources.
+func (m *MergedIterator) Close() {
+	for _, iter := range m.iters {
+		iter.Close()
+	}
+	m.iters = nil
+	m.cur = -1
+}
+
+// init rebuilds the heap once all the sources are positioned.
+func (m *MergedIterator) init(reverse bool) {
+	m.reverse = reverse
+	m.heap = m.heap[:0]
+	for i, iter := range m.iters {
+		if iter.Valid() {
+			m.keys[i] = iter.KeyData()
+			m.heap = append(m.heap, i)
+		}
+	}
+	heap.Init((*mergedHeap)(m))
+	m.resolve()
+}
+
+// step moves a source in the current direction, updating the heap.
+func (m *MergedIterator) step(i int) {
+	iter := m.iters[i]
+	if m.reverse {
+		iter.Prev()
+	} else {
+		iter.Next()
+	}
+	pos := m.heapIndex(i)
+	if iter.Valid() {
+		m.keys[i] = iter.KeyData()
+		heap.Fix((*mergedHeap)(m), pos)
+	} else {
+		m.keys[i] = nil
+		heap.Remove((*mergedHeap)(m), pos)
+	}
+}
+
+// switchDirection repositions all the sources right past the current entry
+// in the new direction.
+func (m *MergedIterator) switchDirection(reverse bool) {
+	key := copyBytes(m.keys[m.cur])
+	cur := m.cur
+	for i, iter := range m.iters {
+		if reverse {
+			iter.SeekForPrev(key)
+		} else {
+			iter.Seek(key)
+		}
+		// the entries with the current key were already yielded by sources
+		// preceding the current one in the new direction, and skipped by all
+		// sources when duplicates are resolved.
+		if iter.Valid() && m.compare(iter.KeyData(), key) == 0 {
+			before := i <= cur
+			if reverse {
+				before = i >= cur
+			}
+			if before || m.policy != KeepAllDuplicates {
+				if reverse {
+					iter.Prev()
+				} else {
+					iter.Next()
+				}
+			}
+		}
+	}
+	m.reverse = reverse
+	m.heap = m.heap[:0]
+	for i, iter := range m.iters {
+		if iter.Valid() {
+			m.keys[i] = iter.KeyData()
+			m.heap = append(m.heap, i)
+		}
+	}
+	heap.Init((*mergedHeap)(m))
+}
+
+// resolve sets the current entry from the top of the heap, skipping the
+// duplicates of its key according to the policy.
+func (m *MergedIterator) resolve() {
+	if len(m.heap) == 0 {
+		m.cur = -1
+		return
+	}
+	m.cur = m.heap[0]
+	if m.policy == KeepAllDuplicates {
+		return
+	}
+
+	// the heap orders the sources of a key in the iteration direction, so
+	// the winner is either the first or the last one popped.
+	takeLast := (m.policy == LastSourceWins) != m.reverse
+	for {
+		top := m.heap[0]
+		if top != m.cur || len(m.heap) == 1 {
+			break
+		}
+		next := m.secondTop()
+		if next < 0 || m.compare(m.keys[next], m.keys[top]) != 0 {
+			break
+		}
+		// two sources hold the key: drop the loser
+		if takeLast {
+			m.step(top)
+			m.cur = m.heap[0]
+		} else {
+			m.step(next)
+		}
+	}
+}
+
+// secondTop returns the source right after the top of the heap.
+func (m *MergedIterator) secondTop() int {
+	h := (*mergedHeap)(m)
+	switch len(m.heap) {
+	case 0, 1:
+		return -1
+	case 2:
+		return m.heap[1]
+	}
+	if h.Less(1, 2) {
+		return m.heap[1]
+	}
+	return m.heap[2]
+}
+
+func (m *MergedIterator) heapIndex(i int) int {
+	for pos, j := range m.heap {
+		if i == j {
+			return pos
+		}
+	}
+	panic("gorocksdb: source not in the merged iterator heap")
+}
+
+// mergedHeap orders the valid sources in the iteration direction, the
+// sources of a same key by index.
+type mergedHeap MergedIterator
+
+func (h *mergedHeap) Len() int { return len(h.heap) }
+
+func (h *mergedHeap) Less(a, b int) bool {
+	i, j := h.heap[a], h.heap[b]
+	c := h.compare(h.keys[i], h.keys[j])
+	if c == 0 {
+		c = i - j
+	}
+	if h.reverse {
+		return c > 0
+	}
+	return c < 0
+}
+
+func (h *mergedHeap) Swap(a, b int) { h.heap[a], h.heap[b] = h.heap[b], h.heap[a] }
+
+func (h *mergedHeap) Push(x interface{}) { h.heap = append(h.heap, x.(int)) }
+
+func (h *mergedHeap) Pop() interface{} {
+	n := len(h.heap)
+	x := h.heap[n-1]
+	h.heap = h.heap[:n-1]
+	return x
+}
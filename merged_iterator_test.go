@@ -0,0 +1,102 @@
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type mergedEntry struct {
+	key, value string
+	source     int
+}
+
+func TestMergedIterator(t *testing.T) {
+	db, cfh, cleanup := newTestDBCF(t, "TestMergedIterator")
+	defer cleanup()
+
+	wo := NewDefaultWriteOptions()
+	ensure.Nil(t, db.PutCF(wo, cfh[0], []byte("a"), []byte("a0")))
+	ensure.Nil(t, db.PutCF(wo, cfh[0], []byte("c"), []byte("c0")))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], []byte("b"), []byte("b1")))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], []byte("c"), []byte("c1")))
+	ensure.Nil(t, db.PutCF(wo, cfh[1], []byte("d"), []byte("d1")))
+
+	ro := NewDefaultReadOptions()
+	newMerged := func(policy DuplicateKeyPolicy) *MergedIterator {
+		m, err := db.NewMergedIterator(ro, cfh, policy)
+		ensure.Nil(t, err)
+		return m
+	}
+
+	// keep all duplicates, forward and backward
+	m := newMerged(KeepAllDuplicates)
+	var entries []mergedEntry
+	for m.SeekToFirst(); m.Valid(); m.Next() {
+		entries = append(entries, mergedEntry{string(m.Key().Data()), string(m.Value().Data()), m.Source()})
+	}
+	ensure.Nil(t, m.Err())
+	ensure.DeepEqual(t, entries, []mergedEntry{
+		{"a", "a0", 0}, {"b", "b1", 1}, {"c", "c0", 0}, {"c", "c1", 1}, {"d", "d1", 1},
+	})
+	entries = nil
+	for m.SeekToLast(); m.Valid(); m.Prev() {
+		entries = append(entries, mergedEntry{string(m.Key().Data()), string(m.Value().Data()), m.Source()})
+	}
+	ensure.DeepEqual(t, entries, []mergedEntry{
+		{"d", "d1", 1}, {"c", "c1", 1}, {"c", "c0", 0}, {"b", "b1", 1}, {"a", "a0", 0},
+	})
+
+	// switch direction in the middle of duplicates
+	m.Seek([]byte("c"))
+	ensure.DeepEqual(t, m.Source(), 0)
+	m.Next()
+	ensure.DeepEqual(t, string(m.Value().Data()), "c1")
+	m.Prev()
+	ensure.DeepEqual(t, string(m.Value().Data()), "c0")
+	m.Prev()
+	ensure.DeepEqual(t, string(m.Value().Data()), "b1")
+	ensure.True(t, m.SourceCF() == cfh[1])
+	m.Close()
+
+	// resolve duplicates
+	for _, test := range []struct {
+		policy DuplicateKeyPolicy
+		value  string
+	}{
+		{FirstSourceWins, "c0"},
+		{LastSourceWins, "c1"},
+	} {
+		m = newMerged(test.policy)
+		var keys, values []string
+		for m.SeekToFirst(); m.Valid(); m.Next() {
+			keys = append(keys, string(m.Key().Data()))
+			values = append(values, string(m.Value().Data()))
+		}
+		ensure.DeepEqual(t, keys, []string{"a", "b", "c", "d"})
+		ensure.DeepEqual(t, values[2], test.value)
+
+		keys = nil
+		for m.SeekForPrev([]byte("c")); m.Valid(); m.Prev() {
+			keys = append(keys, string(m.Key().Data()))
+			values = append(values, string(m.Value().Data()))
+		}
+		ensure.DeepEqual(t, keys, []string{"c", "b", "a"})
+		ensure.DeepEqual(t, values[4], test.value)
+
+		m.Seek([]byte("c"))
+		m.Prev()
+		m.Next()
+		ensure.DeepEqual(t, string(m.Value().Data()), test.value)
+		m.Next()
+		ensure.DeepEqual(t, string(m.Key().Data()), "d")
+
+		// moving past the end is a no-op
+		m.Next()
+		ensure.False(t, m.Valid())
+		m.Next()
+		m.Prev()
+		ensure.False(t, m.Valid())
+		m.Close()
+	}
+}
@@ -0,0 +1,109 @@
+package mergeops
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// StringAppend appends the operands to the existing value, separated by
+// Delimiter.
+type StringAppend struct {
+	Delimiter []byte
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op StringAppend) Name() string {
+	return "mergeops.stringappend." + strconv.Quote(string(op.Delimiter))
+}
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op StringAppend) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	size := len(existingValue)
+	for _, operand := range operands {
+		size += len(op.Delimiter) + len(operand)
+	}
+	value := make([]byte, 0, size)
+	value = append(value, existingValue...)
+	for i, operand := range operands {
+		if i > 0 || existingValue != nil {
+			value = append(value, op.Delimiter...)
+		}
+		value = append(value, operand...)
+	}
+	return value, true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op StringAppend) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, leftOperand, [][]byte{rightOperand})
+}
+
+// CappedListAppend appends lists of elements to the existing list, keeping
+// at most its Max last elements. Values and operands are lists encoded by
+// EncodeList. Max must not change once the database is written.
+type CappedListAppend struct {
+	Max int
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op CappedListAppend) Name() string {
+	return "mergeops.cappedlistappend." + strconv.Itoa(op.Max)
+}
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op CappedListAppend) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	list, ok := DecodeList(existingValue)
+	if !ok {
+		return nil, false
+	}
+	for _, operand := range operands {
+		elems, ok := DecodeList(operand)
+		if !ok {
+			return nil, false
+		}
+		list = append(list, elems...)
+	}
+	if op.Max > 0 && len(list) > op.Max {
+		list = list[len(list)-op.Max:]
+	}
+	return EncodeList(list), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+// Appending both operands and keeping the last elements gives the same
+// result as appending them one after the other.
+func (op CappedListAppend) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, leftOperand, [][]byte{rightOperand})
+}
+
+// EncodeList encodes a list of elements, each prefixed by its length as a
+// varint.
+func EncodeList(elems [][]byte) []byte {
+	size := 0
+	for _, elem := range elems {
+		size += binary.MaxVarintLen64 + len(elem)
+	}
+	buf := make([]byte, 0, size)
+	var n [binary.MaxVarintLen64]byte
+	for _, elem := range elems {
+		buf = append(buf, n[:binary.PutUvarint(n[:], uint64(len(elem)))]...)
+		buf = append(buf, elem...)
+	}
+	return buf
+}
+
+// DecodeList decodes a list encoded by EncodeList, reporting whether b is
+// valid. The elements point into b.
+func DecodeList(b []byte) ([][]byte, bool) {
+	var elems [][]byte
+	for len(b) > 0 {
+		size, n := binary.Uvarint(b)
+		if n <= 0 || size > uint64(len(b)-n) {
+			return nil, false
+		}
+		b = b[n:]
+		elems = append(elems, b[:size:size])
+		b = b[size:]
+	}
+	return elems, true
+}
@@ -0,0 +1,32 @@
+package mergeops
+
+// BitwiseOr ORs the values together byte by byte. Values of different
+// lengths are aligned on their first byte, the shorter being padded with
+// zeros.
+type BitwiseOr struct{}
+
+// Name implements gorocksdb.MergeOperator.
+func (op BitwiseOr) Name() string { return "mergeops.bitwiseor" }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op BitwiseOr) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	size := len(existingValue)
+	for _, operand := range operands {
+		if len(operand) > size {
+			size = len(operand)
+		}
+	}
+	value := make([]byte, size)
+	copy(value, existingValue)
+	for _, operand := range operands {
+		for i, b := range operand {
+			value[i] |= b
+		}
+	}
+	return value, true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op BitwiseOr) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, leftOperand, [][]byte{rightOperand})
+}
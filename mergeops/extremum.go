@@ -0,0 +1,57 @@
+package mergeops
+
+import "bytes"
+
+// Max keeps the greatest of the values. Values are compared with Compare,
+// or bytewise if it is nil, which orders big-endian encoded unsigned
+// integers numerically.
+type Max struct {
+	Compare func(a, b []byte) int
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op Max) Name() string { return "mergeops.max" }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op Max) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	return extremum(op.Compare, 1, existingValue, operands), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op Max) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return extremum(op.Compare, 1, leftOperand, [][]byte{rightOperand}), true
+}
+
+// Min keeps the smallest of the values. Values are compared with Compare,
+// or bytewise if it is nil.
+type Min struct {
+	Compare func(a, b []byte) int
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op Min) Name() string { return "mergeops.min" }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op Min) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	return extremum(op.Compare, -1, existingValue, operands), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op Min) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return extremum(op.Compare, -1, leftOperand, [][]byte{rightOperand}), true
+}
+
+// extremum returns a copy of the value v for which sign*compare(v, w) >= 0 for
+// all the other values w, ignoring a nil first value.
+func extremum(compare func(a, b []byte) int, sign int, first []byte, others [][]byte) []byte {
+	if compare == nil {
+		compare = bytes.Compare
+	}
+	best := first
+	for _, v := range others {
+		if best == nil || sign*compare(v, best) > 0 {
+			best = v
+		}
+	}
+	return append([]byte{}, best...)
+}
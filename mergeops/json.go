@@ -0,0 +1,113 @@
+package mergeops
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// JSONMergePatch applies JSON merge patches (RFC 7396) to a JSON document.
+// Operands are patches: their members replace the members of the document,
+// objects being patched recursively and null members removed. A missing or
+// invalid existing document is replaced by the patches.
+type JSONMergePatch struct{}
+
+// Name implements gorocksdb.MergeOperator.
+func (op JSONMergePatch) Name() string { return "mergeops.jsonmergepatch" }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op JSONMergePatch) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	var doc interface{}
+	if existingValue != nil {
+		if err := unmarshalJSON(existingValue, &doc); err != nil {
+			doc = nil
+		}
+	}
+	for _, operand := range operands {
+		var patch interface{}
+		if err := unmarshalJSON(operand, &patch); err != nil {
+			return nil, false
+		}
+		doc = mergePatch(doc, patch)
+	}
+	value, err := json.Marshal(doc)
+	return value, err == nil
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+// Two patches are combined into one, unless the right one patches a value
+// the left one replaces by a non-object, which no single patch can express.
+func (op JSONMergePatch) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	var left, right interface{}
+	if unmarshalJSON(leftOperand, &left) != nil || unmarshalJSON(rightOperand, &right) != nil {
+		return nil, false
+	}
+	patch, ok := composePatches(left, right)
+	if !ok {
+		return nil, false
+	}
+	value, err := json.Marshal(patch)
+	return value, err == nil
+}
+
+// mergePatch applies a patch to a document as defined by RFC 7396.
+func mergePatch(doc, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	docObj, ok := doc.(map[string]interface{})
+	if !ok {
+		docObj = make(map[string]interface{}, len(patchObj))
+	}
+	for name, value := range patchObj {
+		if value == nil {
+			delete(docObj, name)
+		} else {
+			docObj[name] = mergePatch(docObj[name], value)
+		}
+	}
+	return docObj
+}
+
+// composePatches returns a patch equivalent to applying left then right.
+// It fails when right patches a value left replaces by a non-object, since a
+// patch can't replace a value by an object without merging them.
+func composePatches(left, right interface{}) (interface{}, bool) {
+	rightObj, ok := right.(map[string]interface{})
+	if !ok {
+		return right, true
+	}
+	leftObj, ok := left.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for name, value := range rightObj {
+		prev, exists := leftObj[name]
+		if _, isObj := value.(map[string]interface{}); !exists || !isObj {
+			leftObj[name] = value
+			continue
+		}
+		composed, ok := composePatches(prev, value)
+		if !ok {
+			return nil, false
+		}
+		leftObj[name] = composed
+	}
+	return leftObj, true
+}
+
+// unmarshalJSON decodes a JSON value, keeping numbers as json.Number so that
+// they are written back unchanged.
+func unmarshalJSON(data []byte, v *interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("invalid JSON: trailing data")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Package mergeops provides ready to use merge operators for gorocksdb.
+//
+// Every operator implements both FullMerge and PartialMerge, and can be set
+// on a column family with Options.SetMergeOperator. The name of an operator
+// depends on its parameters, so that a database can't be reopened with an
+// incompatible operator.
+//
+// The operators fail the merge when the existing value or an operand can't be
+// decoded. The Get or Merge that triggered the merge then returns a
+// gorocksdb.MergeError, while the other operations return the corruption
+// status of RocksDB.
+package mergeops
+
+import (
+	"encoding/binary"
+)
+
+// Encoding is the encoding of the integers handled by the numeric operators.
+type Encoding int
+
+const (
+	// FixedEncoding encodes integers on 8 bytes, in little-endian order.
+	// It is the encoding used by DB.Increment.
+	FixedEncoding = Encoding(0)
+	// VarintEncoding encodes integers as varints, zigzag encoded for signed
+	// integers, like encoding/binary.
+	VarintEncoding = Encoding(1)
+)
+
+func (enc Encoding) String() string {
+	if enc == VarintEncoding {
+		return "varint"
+	}
+	return "fixed"
+}
+
+// EncodeUint64 encodes an unsigned integer.
+func (enc Encoding) EncodeUint64(v uint64) []byte {
+	if enc == VarintEncoding {
+		buf := make([]byte, binary.MaxVarintLen64)
+		return buf[:binary.PutUvarint(buf, v)]
+	}
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+	return buf
+}
+
+// DecodeUint64 decodes an unsigned integer, reporting whether b is valid.
+func (enc Encoding) DecodeUint64(b []byte) (uint64, bool) {
+	if enc == VarintEncoding {
+		v, n := binary.Uvarint(b)
+		return v, n > 0 && n == len(b)
+	}
+	if len(b) != 8 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(b), true
+}
+
+// EncodeInt64 encodes a signed integer.
+func (enc Encoding) EncodeInt64(v int64) []byte {
+	if enc == VarintEncoding {
+		buf := make([]byte, binary.MaxVarintLen64)
+		return buf[:binary.PutVarint(buf, v)]
+	}
+	return enc.EncodeUint64(uint64(v))
+}
+
+// DecodeInt64 decodes a signed integer, reporting whether b is valid.
+func (enc Encoding) DecodeInt64(b []byte) (int64, bool) {
+	if enc == VarintEncoding {
+		v, n := binary.Varint(b)
+		return v, n > 0 && n == len(b)
+	}
+	v, ok := enc.DecodeUint64(b)
+	return int64(v), ok
+}
+
+// Uint64Add adds unsigned integers, wrapping around on overflow.
+// A missing existing value counts as zero.
+type Uint64Add struct {
+	Encoding Encoding
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op Uint64Add) Name() string { return "mergeops.uint64add." + op.Encoding.String() }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op Uint64Add) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	var sum uint64
+	if existingValue != nil {
+		v, ok := op.Encoding.DecodeUint64(existingValue)
+		if !ok {
+			return nil, false
+		}
+		sum = v
+	}
+	for _, operand := range operands {
+		v, ok := op.Encoding.DecodeUint64(operand)
+		if !ok {
+			return nil, false
+		}
+		sum += v
+	}
+	return op.Encoding.EncodeUint64(sum), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op Uint64Add) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, nil, [][]byte{leftOperand, rightOperand})
+}
+
+// Int64Add adds signed integers, wrapping around on overflow.
+// A missing existing value counts as zero.
+type Int64Add struct {
+	Encoding Encoding
+}
+
+// Name implements gorocksdb.MergeOperator.
+func (op Int64Add) Name() string { return "mergeops.int64add." + op.Encoding.String() }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op Int64Add) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	var sum int64
+	if existingValue != nil {
+		v, ok := op.Encoding.DecodeInt64(existingValue)
+		if !ok {
+			return nil, false
+		}
+		sum = v
+	}
+	for _, operand := range operands {
+		v, ok := op.Encoding.DecodeInt64(operand)
+		if !ok {
+			return nil, false
+		}
+		sum += v
+	}
+	return op.Encoding.EncodeInt64(sum), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op Int64Add) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, nil, [][]byte{leftOperand, rightOperand})
+}
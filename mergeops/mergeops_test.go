@@ -0,0 +1,132 @@
+package mergeops
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type mergeOperator interface {
+	FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool)
+	PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool)
+	Name() string
+}
+
+// checkMerge checks that merging the operands gives the expected value,
+// whether they are merged all at once or partially merged first.
+func checkMerge(t *testing.T, op mergeOperator, existingValue []byte, operands [][]byte, expected []byte) {
+	value, ok := op.FullMerge([]byte("key"), existingValue, operands)
+	ensure.True(t, ok, op.Name())
+	ensure.DeepEqual(t, value, expected, op.Name())
+
+	if len(operands) < 2 {
+		return
+	}
+	partial := operands[0]
+	for _, operand := range operands[1:] {
+		partial, ok = op.PartialMerge([]byte("key"), partial, operand)
+		ensure.True(t, ok, op.Name())
+	}
+	value, ok = op.FullMerge([]byte("key"), existingValue, [][]byte{partial})
+	ensure.True(t, ok, op.Name())
+	ensure.DeepEqual(t, value, expected, op.Name())
+}
+
+func TestNumericAdd(t *testing.T) {
+	for _, enc := range []Encoding{FixedEncoding, VarintEncoding} {
+		u := Uint64Add{Encoding: enc}
+		checkMerge(t, u, nil, [][]byte{enc.EncodeUint64(1), enc.EncodeUint64(2)}, enc.EncodeUint64(3))
+		checkMerge(t, u, enc.EncodeUint64(10), [][]byte{enc.EncodeUint64(1), enc.EncodeUint64(1 << 40)}, enc.EncodeUint64(11+1<<40))
+		checkMerge(t, u, enc.EncodeUint64(^uint64(0)), [][]byte{enc.EncodeUint64(2)}, enc.EncodeUint64(1))
+
+		i := Int64Add{Encoding: enc}
+		checkMerge(t, i, enc.EncodeInt64(5), [][]byte{enc.EncodeInt64(-7), enc.EncodeInt64(1)}, enc.EncodeInt64(-1))
+		checkMerge(t, i, nil, [][]byte{enc.EncodeInt64(-3), enc.EncodeInt64(-4)}, enc.EncodeInt64(-7))
+
+		_, ok := u.FullMerge(nil, []byte("bad value"), nil)
+		ensure.False(t, ok)
+		_, ok = i.PartialMerge(nil, enc.EncodeInt64(1), []byte{})
+		ensure.False(t, ok)
+	}
+	ensure.NotDeepEqual(t, Uint64Add{FixedEncoding}.Name(), Uint64Add{VarintEncoding}.Name())
+
+	// signed and unsigned fixed additions are interchangeable
+	checkMerge(t, Uint64Add{}, FixedEncoding.EncodeInt64(-5), [][]byte{FixedEncoding.EncodeUint64(3)}, FixedEncoding.EncodeInt64(-2))
+}
+
+func TestExtremum(t *testing.T) {
+	checkMerge(t, Max{}, []byte("b"), [][]byte{[]byte("a"), []byte("c"), []byte("bb")}, []byte("c"))
+	checkMerge(t, Max{}, nil, [][]byte{[]byte("a")}, []byte("a"))
+	checkMerge(t, Min{}, []byte("b"), [][]byte{[]byte("c"), []byte("ab"), []byte("b")}, []byte("ab"))
+
+	byLen := func(a, b []byte) int { return len(a) - len(b) }
+	checkMerge(t, Max{Compare: byLen}, []byte("zz"), [][]byte{[]byte("aaa"), []byte("b")}, []byte("aaa"))
+}
+
+func TestStringAppend(t *testing.T) {
+	op := StringAppend{Delimiter: []byte(",")}
+	checkMerge(t, op, []byte("a"), [][]byte{[]byte("b"), []byte("c")}, []byte("a,b,c"))
+	checkMerge(t, op, nil, [][]byte{[]byte("b"), []byte("c")}, []byte("b,c"))
+	checkMerge(t, op, []byte{}, [][]byte{[]byte("b")}, []byte(",b"))
+	checkMerge(t, StringAppend{}, []byte("a"), [][]byte{[]byte("b"), []byte("c")}, []byte("abc"))
+	ensure.NotDeepEqual(t, op.Name(), StringAppend{}.Name())
+}
+
+func TestCappedListAppend(t *testing.T) {
+	list := func(elems ...string) []byte {
+		var b [][]byte
+		for _, elem := range elems {
+			b = append(b, []byte(elem))
+		}
+		return EncodeList(b)
+	}
+	op := CappedListAppend{Max: 3}
+	checkMerge(t, op, list("a"), [][]byte{list("b"), list("c", "d"), list("e")}, list("c", "d", "e"))
+	checkMerge(t, op, nil, [][]byte{list("a", ""), list("b")}, list("a", "", "b"))
+	checkMerge(t, CappedListAppend{}, list("a"), [][]byte{list("b", "c", "d")}, list("a", "b", "c", "d"))
+
+	_, ok := op.FullMerge(nil, []byte{5, 'a'}, nil)
+	ensure.False(t, ok)
+}
+
+func TestSortedSetUnion(t *testing.T) {
+	set := func(elems ...string) []byte {
+		var b [][]byte
+		for _, elem := range elems {
+			b = append(b, []byte(elem))
+		}
+		return EncodeList(b)
+	}
+	op := SortedSetUnion{}
+	checkMerge(t, op, set("b", "d"), [][]byte{set("a", "d"), set("c", "e")}, set("a", "b", "c", "d", "e"))
+	checkMerge(t, op, nil, [][]byte{set("c", "a", "c"), set("b")}, set("a", "b", "c"))
+	checkMerge(t, op, set("x"), [][]byte{set()}, set("x"))
+}
+
+func TestBitwiseOr(t *testing.T) {
+	op := BitwiseOr{}
+	checkMerge(t, op, []byte{0x01, 0x10}, [][]byte{{0x02}, {0x00, 0x01, 0x80}}, []byte{0x03, 0x11, 0x80})
+	checkMerge(t, op, nil, [][]byte{{0xf0}, {0x0f}}, []byte{0xff})
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	op := JSONMergePatch{}
+	checkMerge(t, op,
+		[]byte(`{"a":"b","c":{"d":"e","f":"g"},"n":12345678901234567890}`),
+		[][]byte{[]byte(`{"a":"z","c":{"f":null}}`), []byte(`{"c":{"h":[1,2]},"i":true}`)},
+		[]byte(`{"a":"z","c":{"d":"e","h":[1,2]},"i":true,"n":12345678901234567890}`))
+	checkMerge(t, op, nil, [][]byte{[]byte(`{"a":{"b":null,"c":1}}`)}, []byte(`{"a":{"c":1}}`))
+	checkMerge(t, op, []byte(`{"a":1}`), [][]byte{[]byte(`{"a":null}`), []byte(`[1]`)}, []byte(`[1]`))
+
+	// a patch can't replace a value by an object
+	_, ok := op.PartialMerge(nil, []byte(`[1]`), []byte(`{"b":2}`))
+	ensure.False(t, ok)
+	_, ok = op.PartialMerge(nil, []byte(`{"a":1}`), []byte(`{"a":{"b":2}}`))
+	ensure.False(t, ok)
+	value, ok := op.FullMerge(nil, []byte(`{"a":{"c":3}}`), [][]byte{[]byte(`{"a":1}`), []byte(`{"a":{"b":2}}`)})
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, value, []byte(`{"a":{"b":2}}`))
+
+	_, ok = op.FullMerge(nil, nil, [][]byte{[]byte(`{"a":`)})
+	ensure.False(t, ok)
+}
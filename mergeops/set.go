@@ -0,0 +1,77 @@
+package mergeops
+
+import (
+	"bytes"
+	"sort"
+)
+
+// SortedSetUnion merges sets of elements, kept as lists sorted bytewise and
+// without duplicates. Values and operands are lists encoded by EncodeList,
+// and may be unsorted or hold duplicates: the merged value is always a
+// sorted set.
+type SortedSetUnion struct{}
+
+// Name implements gorocksdb.MergeOperator.
+func (op SortedSetUnion) Name() string { return "mergeops.sortedsetunion" }
+
+// FullMerge implements gorocksdb.MergeOperator.
+func (op SortedSetUnion) FullMerge(key, existingValue []byte, operands [][]byte) ([]byte, bool) {
+	set, ok := decodeSet(existingValue)
+	if !ok {
+		return nil, false
+	}
+	for _, operand := range operands {
+		other, ok := decodeSet(operand)
+		if !ok {
+			return nil, false
+		}
+		set = union(set, other)
+	}
+	return EncodeList(set), true
+}
+
+// PartialMerge implements gorocksdb.MergeOperator.
+func (op SortedSetUnion) PartialMerge(key, leftOperand, rightOperand []byte) ([]byte, bool) {
+	return op.FullMerge(key, leftOperand, [][]byte{rightOperand})
+}
+
+// decodeSet decodes a list, sorting it and removing its duplicates if needed.
+func decodeSet(b []byte) ([][]byte, bool) {
+	elems, ok := DecodeList(b)
+	if !ok {
+		return nil, false
+	}
+	for i := 1; i < len(elems); i++ {
+		if bytes.Compare(elems[i-1], elems[i]) >= 0 {
+			sort.Slice(elems, func(i, j int) bool { return bytes.Compare(elems[i], elems[j]) < 0 })
+			return union(elems, nil), true
+		}
+	}
+	return elems, true
+}
+
+// union merges two sorted lists, removing the duplicates.
+func union(a, b [][]byte) [][]byte {
+	set := make([][]byte, 0, len(a)+len(b))
+	push := func(elem []byte) {
+		if len(set) == 0 || !bytes.Equal(set[len(set)-1], elem) {
+			set = append(set, elem)
+		}
+	}
+	for len(a) > 0 && len(b) > 0 {
+		if bytes.Compare(a[0], b[0]) <= 0 {
+			push(a[0])
+			a = a[1:]
+		} else {
+			push(b[0])
+			b = b[1:]
+		}
+	}
+	for _, elem := range a {
+		push(elem)
+	}
+	for _, elem := range b {
+		push(elem)
+	}
+	return set
+}
@@ -0,0 +1,208 @@
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"unsafe"
+)
+
+// ErrValueSizeSoftLimitExceeded is returned for the keys of a MultiGetBatch
+// that were not read because the value size soft limit was exceeded.
+var ErrValueSizeSoftLimitExceeded = errors.New("Operation aborted: value size soft limit exceeded")
+
+// multiGetChunkSize is the number of keys looked up per call into RocksDB,
+// which bounds the work done past the value size soft limit.
+const multiGetChunkSize = 64
+
+// multiGetMaxPooledBuffer is the largest value buffer kept in the pool.
+const multiGetMaxPooledBuffer = 1 << 20
+
+// KeyRequest is a key to look up with MultiGetBatch.
+type KeyRequest struct {
+	// CF is the column family of the key, nil for the default column family.
+	CF  *ColumnFamilyHandle
+	Key []byte
+}
+
+// KeyResult is the result of looking up one KeyRequest.
+type KeyResult struct {
+	// Value is the value of the key, valid until the result is released.
+	Value []byte
+	// Found reports whether the key exists.
+	Found bool
+	// Err is the error looking up the key, if any.
+	Err error
+}
+
+// MultiGetResult holds the results of MultiGetBatch.
+type MultiGetResult struct {
+	// Results holds one result per request, in the order of the requests.
+	Results []KeyResult
+
+	order   []int
+	offsets []int
+	buf     []byte
+}
+
+var multiGetResultPool = sync.Pool{
+	New: func() interface{} { return new(MultiGetResult) },
+}
+
+// Release returns the result buffers to a pool so they can be reused by
+// later calls. Neither the result nor its values may be used afterwards.
+func (r *MultiGetResult) Release() {
+	for i := range r.Results {
+		r.Results[i] = KeyResult{}
+	}
+	if cap(r.buf) > multiGetMaxPooledBuffer {
+		r.buf = nil
+	}
+	multiGetResultPool.Put(r)
+}
+
+func (r *MultiGetResult) reset(n int) {
+	if cap(r.Results) < n {
+		r.Results = make([]KeyResult, n)
+		r.order = make([]int, n)
+		r.offsets = make([]int, n)
+	}
+	r.Results = r.Results[:n]
+	r.order = r.order[:n]
+	r.offsets = r.offsets[:n]
+	r.buf = r.buf[:0]
+	for i := range r.order {
+		r.order[i] = i
+	}
+}
+
+// MultiGetBatch looks up the given keys, which may belong to different
+// column families, and returns a result per key.
+//
+// The keys are sorted by column family and key before being looked up, so
+// callers don't need to. A failure to read one key doesn't fail the others.
+// If opts has a value size soft limit, the keys following the one crossing
+// the limit are not read and fail with ErrValueSizeSoftLimitExceeded.
+//
+// The values are copied into a buffer reused across calls; Release must be
+// called once the result is no longer used.
+func (db *DB) MultiGetBatch(opts *ReadOptions, reqs []KeyRequest) *MultiGetResult {
+	res := multiGetResultPool.Get().(*MultiGetResult)
+	res.reset(len(reqs))
+
+	sort.Slice(res.order, func(i, j int) bool {
+		a, b := &reqs[res.order[i]], &reqs[res.order[j]]
+		if a.CF != b.CF {
+			return cfOrder(a.CF) < cfOrder(b.CF)
+		}
+		return bytes.Compare(a.Key, b.Key) < 0
+	})
+
+	var (
+		limit = opts.valueSizeSoftLimit
+		total uint64
+	)
+	for start := 0; start < len(res.order); {
+		if limit > 0 && total > limit {
+			for _, i := range res.order[start:] {
+				res.Results[i].Err = ErrValueSizeSoftLimitExceeded
+			}
+			break
+		}
+
+		// a chunk never mixes the default column family with others,
+		// since they are read with different functions.
+		end := start + 1
+		for end < len(res.order) && end-start < multiGetChunkSize &&
+			(reqs[res.order[end]].CF == nil) == (reqs[res.order[start]].CF == nil) {
+			end++
+		}
+		db.multiGetChunk(opts, reqs, res, res.order[start:end], &total)
+		start = end
+	}
+
+	for i := range res.Results {
+		if r := &res.Results[i]; r.Found {
+			end := res.offsets[i] + len(r.Value)
+			r.Value = res.buf[res.offsets[i]:end:end]
+		}
+	}
+	return res
+}
+
+// multiGetChunk reads the keys of the given requests, all in the default
+// column family or all not, appending the values found to the result buffer.
+func (db *DB) multiGetChunk(opts *ReadOptions, reqs []KeyRequest, res *MultiGetResult, chunk []int, total *uint64) {
+	keys := make([][]byte, len(chunk))
+	for j, i := range chunk {
+		keys[j] = reqs[i].Key
+	}
+	cKeys, cKeySizes := byteSlicesToCSlices(keys)
+	defer cKeys.Destroy()
+	vals := make(charsSlice, len(chunk))
+	valSizes := make(sizeTSlice, len(chunk))
+	rocksErrs := make(charsSlice, len(chunk))
+
+	if reqs[chunk[0]].CF == nil {
+		C.rocksdb_multi_get(
+			db.c,
+			opts.c,
+			C.size_t(len(chunk)),
+			cKeys.c(),
+			cKeySizes.c(),
+			vals.c(),
+			valSizes.c(),
+			rocksErrs.c(),
+		)
+	} else {
+		cfs := make(ColumnFamilyHandles, len(chunk))
+		for j, i := range chunk {
+			cfs[j] = reqs[i].CF
+		}
+		C.rocksdb_multi_get_cf(
+			db.c,
+			opts.c,
+			cfs.toCSlice().c(),
+			C.size_t(len(chunk)),
+			cKeys.c(),
+			cKeySizes.c(),
+			vals.c(),
+			valSizes.c(),
+			rocksErrs.c(),
+		)
+	}
+
+	limit := opts.valueSizeSoftLimit
+	for j, i := range chunk {
+		r := &res.Results[i]
+		switch {
+		case rocksErrs[j] != nil:
+			r.Err = errors.New(C.GoString(rocksErrs[j]))
+			C.free(unsafe.Pointer(rocksErrs[j]))
+		case limit > 0 && *total > limit:
+			r.Err = ErrValueSizeSoftLimitExceeded
+		case vals[j] != nil:
+			r.Found = true
+			// the buffer may still grow, so Value is rebased on it once
+			// all the chunks are read.
+			res.offsets[i] = len(res.buf)
+			res.buf = append(res.buf, charToByte(vals[j], valSizes[j])...)
+			r.Value = res.buf[res.offsets[i]:len(res.buf):len(res.buf)]
+			*total += uint64(valSizes[j])
+		}
+		C.free(unsafe.Pointer(vals[j]))
+	}
+}
+
+// cfOrder returns an ordering key grouping the requests by column family,
+// with the default column family first.
+func cfOrder(cf *ColumnFamilyHandle) uintptr {
+	if cf == nil {
+		return 0
+	}
+	return uintptr(unsafe.Pointer(cf.c))
+}
@@ -25,6 +25,9 @@ const (
 	LZ4HCCompression  = CompressionType(C.rocksdb_lz4hc_compression)
 	XpressCompression = CompressionType(C.rocksdb_xpress_compression)
 	ZSTDCompression   = CompressionType(C.rocksdb_zstd_compression)
+	// DisableCompressionOption is the default bottommost compression, which
+	// uses the compression of the other levels.
+	DisableCompressionOption = CompressionType(0xff)
 )
 
 // CompactionStyle specifies the compaction style.
@@ -73,16 +76,33 @@ type Options struct {
 	cmo  *C.rocksdb_mergeoperator_t
 	cst  *C.rocksdb_slicetransform_t
 	ccf  *C.rocksdb_compactionfilter_t
+
+	// Settings the C API can't read back, kept for their getters, nil
+	// unless they were set through the setters.
+	compressionPerLevel                  []CompressionType
+	maxBytesForLevelMultiplierAdditional []int
+	dbLogDir                             *string
+	walDir                               *string
+
+	// Names checked by CheckComparatorsAndMergeOperators, empty if unknown.
+	comparatorName    string
+	mergeOperatorName string
+	// mergeFailures logs the failures of the Go merge operator.
+	mergeFailures *mergeFailureLog
 }
 
 // NewDefaultOptions creates the default Options.
 func NewDefaultOptions() *Options {
-	return NewNativeOptions(C.rocksdb_options_create())
+	opts := NewNativeOptions(C.rocksdb_options_create())
+	opts.comparatorName = bytewiseComparatorName
+	return opts
 }
 
 // NewNativeOptions creates a Options object.
 func NewNativeOptions(c *C.rocksdb_options_t) *Options {
-	return &Options{c: c}
+	opts := &Options{c: c}
+	trackAlloc("Options", opts)
+	return opts
 }
 
 // GetOptionsFromString creates a Options object from existing opt and string.
@@ -105,6 +125,8 @@ func GetOptionsFromString(base *Options, optStr string) (*Options, error) {
 		defer C.free(unsafe.Pointer(cErr))
 		return nil, errors.New(C.GoString(cErr))
 	}
+	// the string may set another comparator
+	newOpt.comparatorName = ""
 
 	return newOpt, nil
 }
@@ -125,6 +147,15 @@ func (opts *Options) SetCompactionFilter(value CompactionFilter) {
 	C.rocksdb_options_set_compaction_filter(opts.c, opts.ccf)
 }
 
+// SetCompactionFilterFactory sets the factory creating a compaction filter
+// for each compaction. A compaction filter set with SetCompactionFilter
+// takes precedence over the factory.
+// Default: nil
+func (opts *Options) SetCompactionFilterFactory(value CompactionFilterFactory) {
+	idx := registerCompactionFilterFactory(value)
+	C.rocksdb_options_set_compaction_filter_factory(opts.c, C.gorocksdb_compactionfilterfactory_create(C.uintptr_t(idx)))
+}
+
 // SetComparator sets the comparator which define the order of keys in the table.
 // Default: a comparator that uses lexicographic byte-wise ordering
 func (opts *Options) SetComparator(value Comparator) {
@@ -135,6 +166,7 @@ func (opts *Options) SetComparator(value Comparator) {
 		opts.ccmp = C.gorocksdb_comparator_create(C.uintptr_t(idx))
 	}
 	C.rocksdb_options_set_comparator(opts.c, opts.ccmp)
+	opts.comparatorName = value.Name()
 }
 
 // SetMergeOperator sets the merge operator which will be called
@@ -144,10 +176,12 @@ func (opts *Options) SetMergeOperator(value MergeOperator) {
 	if nmo, ok := value.(nativeMergeOperator); ok {
 		opts.cmo = nmo.c
 	} else {
-		idx := registerMergeOperator(value)
+		idx, failures := registerMergeOperator(value)
 		opts.cmo = C.gorocksdb_mergeoperator_create(C.uintptr_t(idx))
+		opts.mergeFailures = failures
 	}
 	C.rocksdb_options_set_merge_operator(opts.c, opts.cmo)
+	opts.mergeOperatorName = value.Name()
 }
 
 // A single CompactionFilter instance to call into during compaction.
@@ -424,6 +458,13 @@ func (opts *Options) SetCompressionPerLevel(value []CompressionType) {
 	}
 
 	C.rocksdb_options_set_compression_per_level(opts.c, &cLevels[0], C.size_t(len(value)))
+	opts.compressionPerLevel = append([]CompressionType(nil), value...)
+}
+
+// GetCompressionPerLevel returns the compression algorithms set by
+// SetCompressionPerLevel, nil if they were not set.
+func (opts *Options) GetCompressionPerLevel() []CompressionType {
+	return append([]CompressionType(nil), opts.compressionPerLevel...)
 }
 
 // SetMinLevelToCompress sets the start level to use compression.
@@ -638,6 +679,13 @@ func (opts *Options) SetMaxBytesForLevelMultiplierAdditional(value []int) {
 	}
 
 	C.rocksdb_options_set_max_bytes_for_level_multiplier_additional(opts.c, &cLevels[0], C.size_t(len(value)))
+	opts.maxBytesForLevelMultiplierAdditional = append([]int(nil), value...)
+}
+
+// GetMaxBytesForLevelMultiplierAdditional returns the multipliers set by
+// SetMaxBytesForLevelMultiplierAdditional, nil if they were not set.
+func (opts *Options) GetMaxBytesForLevelMultiplierAdditional() []int {
+	return append([]int(nil), opts.maxBytesForLevelMultiplierAdditional...)
 }
 
 // SetUseFsync enable/disable fsync.
@@ -662,6 +710,16 @@ func (opts *Options) SetDbLogDir(value string) {
 	cvalue := C.CString(value)
 	defer C.free(unsafe.Pointer(cvalue))
 	C.rocksdb_options_set_db_log_dir(opts.c, cvalue)
+	opts.dbLogDir = &value
+}
+
+// GetDbLogDir returns the info LOG dir set by SetDbLogDir, empty if it was
+// not set.
+func (opts *Options) GetDbLogDir() string {
+	if opts.dbLogDir == nil {
+		return ""
+	}
+	return *opts.dbLogDir
 }
 
 // SetWalDir specifies the absolute dir path for write-ahead logs (WAL).
@@ -674,6 +732,16 @@ func (opts *Options) SetWalDir(value string) {
 	cvalue := C.CString(value)
 	defer C.free(unsafe.Pointer(cvalue))
 	C.rocksdb_options_set_wal_dir(opts.c, cvalue)
+	opts.walDir = &value
+}
+
+// GetWalDir returns the write-ahead logs dir set by SetWalDir, empty if it
+// was not set.
+func (opts *Options) GetWalDir() string {
+	if opts.walDir == nil {
+		return ""
+	}
+	return *opts.walDir
 }
 
 // SetDeleteObsoleteFilesPeriodMicros sets the periodicity
@@ -1027,7 +1095,7 @@ func (opts *Options) SetInplaceUpdateNumLocks(value int) {
 //
 // Dynamically changeable through SetOptions() API
 func (opts *Options) SetReportBgIoStats(value bool) {
-	C.rocksdb_options_set_inplace_update_support(opts.c, boolToChar(value))
+	C.rocksdb_options_set_report_bg_io_stats(opts.c, C.int(btoi(value)))
 }
 
 // SetMemtableHugePageSize sets the page size for huge page for
@@ -1222,6 +1290,7 @@ func (opts *Options) SetSkipStatsUpdateOnDbOpen(value bool) {
 
 // Destroy deallocates the Options object.
 func (opts *Options) Destroy() {
+	trackFree(opts)
 	C.rocksdb_options_destroy(opts.c)
 	if opts.ccmp != nil {
 		C.rocksdb_comparator_destroy(opts.ccmp)
@@ -37,11 +37,14 @@ func NewDefaultBlockBasedTableOptions() *BlockBasedTableOptions {
 
 // NewNativeBlockBasedTableOptions creates a BlockBasedTableOptions object.
 func NewNativeBlockBasedTableOptions(c *C.rocksdb_block_based_table_options_t) *BlockBasedTableOptions {
-	return &BlockBasedTableOptions{c: c}
+	opts := &BlockBasedTableOptions{c: c}
+	trackAlloc("BlockBasedTableOptions", opts)
+	return opts
 }
 
 // Destroy deallocates the BlockBasedTableOptions object.
 func (opts *BlockBasedTableOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_block_based_options_destroy(opts.c)
 	opts.c = nil
 	opts.cache = nil
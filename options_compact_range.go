@@ -0,0 +1,81 @@
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// BottommostLevelCompaction controls whether a manual compaction compacts
+// the bottommost level.
+type BottommostLevelCompaction uint
+
+const (
+	// BottommostLevelCompactionSkip skips the bottommost level.
+	BottommostLevelCompactionSkip = BottommostLevelCompaction(0)
+	// BottommostLevelCompactionIfHaveCompactionFilter only compacts the
+	// bottommost level if the column family has a compaction filter.
+	BottommostLevelCompactionIfHaveCompactionFilter = BottommostLevelCompaction(1)
+	// BottommostLevelCompactionForce always compacts the bottommost level.
+	BottommostLevelCompactionForce = BottommostLevelCompaction(2)
+	// BottommostLevelCompactionForceOptimized always compacts the bottommost
+	// level, but skips the files created by this compaction.
+	BottommostLevelCompactionForceOptimized = BottommostLevelCompaction(3)
+)
+
+// CompactRangeOptions represent all of the available options when compacting
+// a range of keys with CompactRangeOpt.
+//
+// The C API doesn't expose allow_write_stall nor max_subcompactions for
+// manual compactions, so they keep their defaults; max_subcompactions can
+// only be set database-wide, with Options.SetMaxSubcompactions. Compacting a
+// given set of files, as RocksDB's DB::CompactFiles does, isn't exposed
+// either.
+type CompactRangeOptions struct {
+	c *C.rocksdb_compactoptions_t
+}
+
+// NewDefaultCompactRangeOptions creates a default CompactRangeOptions object.
+func NewDefaultCompactRangeOptions() *CompactRangeOptions {
+	return NewNativeCompactRangeOptions(C.rocksdb_compactoptions_create())
+}
+
+// NewNativeCompactRangeOptions creates a CompactRangeOptions object.
+func NewNativeCompactRangeOptions(c *C.rocksdb_compactoptions_t) *CompactRangeOptions {
+	opts := &CompactRangeOptions{c}
+	trackAlloc("CompactRangeOptions", opts)
+	return opts
+}
+
+// SetExclusiveManualCompaction specify if the compaction runs exclusively,
+// without automatic compactions running in parallel.
+// Default: true
+func (opts *CompactRangeOptions) SetExclusiveManualCompaction(value bool) {
+	C.rocksdb_compactoptions_set_exclusive_manual_compaction(opts.c, boolToChar(value))
+}
+
+// SetBottommostLevelCompaction specify whether the bottommost level is
+// compacted.
+// Default: BottommostLevelCompactionIfHaveCompactionFilter
+func (opts *CompactRangeOptions) SetBottommostLevelCompaction(value BottommostLevelCompaction) {
+	C.rocksdb_compactoptions_set_bottommost_level_compaction(opts.c, C.uchar(value))
+}
+
+// SetChangeLevel specify if the compacted files are moved to the minimum
+// level capable of holding the data, or to the target level.
+// Default: false
+func (opts *CompactRangeOptions) SetChangeLevel(value bool) {
+	C.rocksdb_compactoptions_set_change_level(opts.c, boolToChar(value))
+}
+
+// SetTargetLevel specify the level the compacted files are moved to when
+// change level is set, -1 meaning the minimum level capable of holding the
+// data.
+// Default: -1
+func (opts *CompactRangeOptions) SetTargetLevel(value int) {
+	C.rocksdb_compactoptions_set_target_level(opts.c, C.int(value))
+}
+
+// Destroy deallocates the CompactRangeOptions object.
+func (opts *CompactRangeOptions) Destroy() {
+	trackFree(opts)
+	C.rocksdb_compactoptions_destroy(opts.c)
+	opts.c = nil
+}
@@ -15,11 +15,14 @@ func NewDefaultEnvOptions() *EnvOptions {
 
 // NewNativeEnvOptions creates a EnvOptions object.
 func NewNativeEnvOptions(c *C.rocksdb_envoptions_t) *EnvOptions {
-	return &EnvOptions{c: c}
+	opts := &EnvOptions{c: c}
+	trackAlloc("EnvOptions", opts)
+	return opts
 }
 
 // Destroy deallocates the EnvOptions object.
 func (opts *EnvOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_envoptions_destroy(opts.c)
 	opts.c = nil
 }
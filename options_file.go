@@ -0,0 +1,332 @@
+package gorocksdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// bytewiseComparatorName is the name of the default comparator.
+	bytewiseComparatorName = "leveldb.BytewiseComparator"
+	optionsFilePrefix      = "OPTIONS-"
+)
+
+// LoadedOptions holds the options of a database read from an OPTIONS file,
+// which RocksDB writes each time the database is opened or its options are
+// changed. They can be passed as is to OpenDbColumnFamilies to reopen the
+// database with the column families and settings it was last opened with.
+//
+// Only the settings that can be described by a string are loaded: a custom
+// comparator, merge operator, compaction filter or prefix extractor must be
+// set again on the column family options.
+type LoadedOptions struct {
+	// DBOptions holds the database options.
+	DBOptions *Options
+	// ColumnFamilyNames holds the names of the column families.
+	ColumnFamilyNames []string
+	// ColumnFamilyOptions holds the options of the column families, in the
+	// order of ColumnFamilyNames.
+	ColumnFamilyOptions []*Options
+}
+
+// Destroy deallocates the loaded options.
+func (o *LoadedOptions) Destroy() {
+	if o.DBOptions != nil {
+		o.DBOptions.Destroy()
+		o.DBOptions = nil
+	}
+	for _, opts := range o.ColumnFamilyOptions {
+		opts.Destroy()
+	}
+	o.ColumnFamilyNames = nil
+	o.ColumnFamilyOptions = nil
+}
+
+// LoadOptionsFromFile loads the options of a database from an OPTIONS file.
+// The options are parsed by GetOptionsFromString, so the options this version
+// of RocksDB doesn't know about are reported as errors.
+func LoadOptionsFromFile(file string) (*LoadedOptions, error) {
+	parsed, err := readOptionsFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	dbOpts, err := GetOptionsFromString(nil, parsed.db.String())
+	if err != nil {
+		return nil, err
+	}
+	loaded := &LoadedOptions{DBOptions: dbOpts}
+	for _, cf := range parsed.cfs {
+		opts, err := GetOptionsFromString(nil, cf.String())
+		if err != nil {
+			loaded.Destroy()
+			return nil, fmt.Errorf("%s (column family %q)", err, cf.name)
+		}
+		loaded.ColumnFamilyNames = append(loaded.ColumnFamilyNames, cf.name)
+		loaded.ColumnFamilyOptions = append(loaded.ColumnFamilyOptions, opts)
+	}
+	parsed.recordNames(loaded)
+	return loaded, nil
+}
+
+// CheckComparatorsAndMergeOperators checks that the database at path can be
+// opened with the given column families and options, as read from its
+// latest OPTIONS file: every column family of the database must be given,
+// with the comparator it was created with, and with the same merge operator
+// if one is set on both sides. Unlike RocksDB's CheckOptionsCompatibility,
+// the other options, such as the prefix extractor or the table factory, are
+// not checked.
+//
+// The comparator and merge operator names are compared as returned by their
+// Name method. The comparator of the options created by NewNativeOptions or
+// GetOptionsFromString is unknown until set by SetComparator, and is not
+// checked.
+func CheckComparatorsAndMergeOperators(path string, cfNames []string, cfOpts []*Options) error {
+	if len(cfNames) != len(cfOpts) {
+		return errors.New("Invalid argument: must provide the same number of column family names and options")
+	}
+	file, err := latestOptionsFile(path)
+	if err != nil {
+		return err
+	}
+	parsed, err := readOptionsFile(file)
+	if err != nil {
+		return err
+	}
+
+	given := make(map[string]*Options, len(cfNames))
+	for i, name := range cfNames {
+		given[name] = cfOpts[i]
+	}
+	for _, cf := range parsed.cfs {
+		opts, ok := given[cf.name]
+		if !ok {
+			return fmt.Errorf("Invalid argument: column family %q of the database is missing", cf.name)
+		}
+
+		if expected := cf.options.get("comparator", bytewiseComparatorName); opts.comparatorName != "" &&
+			opts.comparatorName != expected {
+			return fmt.Errorf("Invalid argument: column family %q was created with comparator %q, not %q",
+				cf.name, expected, opts.comparatorName)
+		}
+		if expected := cf.options.get("merge_operator", ""); expected != "" && opts.mergeOperatorName != "" &&
+			opts.mergeOperatorName != expected {
+			return fmt.Errorf("Invalid argument: column family %q was created with merge operator %q, not %q",
+				cf.name, expected, opts.mergeOperatorName)
+		}
+	}
+	return nil
+}
+
+// latestOptionsFile returns the OPTIONS file of the database at path with
+// the highest number.
+func latestOptionsFile(path string) (string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		latest string
+		number uint64
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, optionsFilePrefix) {
+			continue
+		}
+		n, err := strconv.ParseUint(name[len(optionsFilePrefix):], 10, 64)
+		if err != nil {
+			// skip the temporary files
+			continue
+		}
+		if latest == "" || n > number {
+			latest, number = name, n
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("NotFound: no OPTIONS file in %s", path)
+	}
+	return filepath.Join(path, latest), nil
+}
+
+// optionsSection holds the options of a section of an OPTIONS file.
+type optionsSection map[string]string
+
+func (s optionsSection) get(name, defaultValue string) string {
+	if value, ok := s[name]; ok && value != "nullptr" {
+		return value
+	}
+	return defaultValue
+}
+
+// String returns the options in the format of GetOptionsFromString, leaving
+// out the objects set to nullptr, which are the defaults, and the table
+// factory, set from the table options section.
+func (s optionsSection) String() string {
+	names := make([]string, 0, len(s))
+	for name, value := range s {
+		if value == "nullptr" || name == "table_factory" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + "=" + s[name] + ";")
+	}
+	return b.String()
+}
+
+// optionsFileCF holds the sections of a column family in an OPTIONS file.
+type optionsFileCF struct {
+	name         string
+	options      optionsSection
+	table        string
+	tableOptions optionsSection
+}
+
+func (cf *optionsFileCF) String() string {
+	s := cf.options.String()
+	switch cf.table {
+	case "BlockBasedTable":
+		s += "block_based_table_factory={" + cf.tableOptions.String() + "};"
+	case "PlainTable":
+		s += "plain_table_factory={" + cf.tableOptions.String() + "};"
+	}
+	return s
+}
+
+// optionsFile holds the parsed sections of an OPTIONS file.
+type optionsFile struct {
+	db  optionsSection
+	cfs []*optionsFileCF
+}
+
+// recordNames records the comparator and merge operator names of the column
+// families on their loaded options, for CheckComparatorsAndMergeOperators.
+func (f *optionsFile) recordNames(loaded *LoadedOptions) {
+	for _, cf := range f.cfs {
+		for i, name := range loaded.ColumnFamilyNames {
+			if name == cf.name {
+				opts := loaded.ColumnFamilyOptions[i]
+				opts.comparatorName = cf.options.get("comparator", bytewiseComparatorName)
+				opts.mergeOperatorName = cf.options.get("merge_operator", "")
+			}
+		}
+	}
+}
+
+func (f *optionsFile) cf(name string) *optionsFileCF {
+	for _, cf := range f.cfs {
+		if cf.name == name {
+			return cf
+		}
+	}
+	cf := &optionsFileCF{name: name, options: optionsSection{}}
+	f.cfs = append(f.cfs, cf)
+	return cf
+}
+
+func readOptionsFile(file string) (*optionsFile, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	parsed, err := parseOptionsFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s (%s)", err, file)
+	}
+	return parsed, nil
+}
+
+// parseOptionsFile parses the INI-like format of the OPTIONS files:
+//
+//	[DBOptions]
+//	  name=value
+//	[CFOptions "name"]
+//	  name=value
+//	[TableOptions/BlockBasedTable "name"]
+//	  name=value
+func parseOptionsFile(r io.Reader) (*optionsFile, error) {
+	var (
+		parsed  = &optionsFile{db: optionsSection{}}
+		section optionsSection
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		if text[0] == '[' {
+			if text[len(text)-1] != ']' {
+				return nil, fmt.Errorf("Invalid argument: line %d: malformed section header", line)
+			}
+			kind, name := text[1:len(text)-1], ""
+			if i := strings.IndexByte(kind, ' '); i >= 0 {
+				kind, name = kind[:i], strings.Trim(strings.TrimSpace(kind[i:]), `"`)
+			}
+			switch {
+			case kind == "Version":
+				section = optionsSection{}
+			case kind == "DBOptions":
+				section = parsed.db
+			case kind == "CFOptions":
+				section = parsed.cf(name).options
+			case strings.HasPrefix(kind, "TableOptions/"):
+				cf := parsed.cf(name)
+				cf.table = strings.TrimPrefix(kind, "TableOptions/")
+				cf.tableOptions = optionsSection{}
+				section = cf.tableOptions
+			default:
+				return nil, fmt.Errorf("Invalid argument: line %d: unknown section %q", line, kind)
+			}
+			continue
+		}
+
+		if section == nil {
+			return nil, fmt.Errorf("Invalid argument: line %d: option outside of a section", line)
+		}
+		i := strings.IndexByte(text, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("Invalid argument: line %d: malformed option", line)
+		}
+		section[strings.TrimSpace(text[:i])] = unescapeOptionValue(strings.TrimSpace(text[i+1:]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// unescapeOptionValue removes the backslashes RocksDB escapes the special
+// characters of the values with.
+func unescapeOptionValue(value string) string {
+	if strings.IndexByte(value, '\\') < 0 {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) {
+			i++
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package gorocksdb
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+const testOptionsFile = `# This is a RocksDB option file.
+[Version]
+  rocksdb_version=6.20.3
+  options_file_version=1.1
+
+[DBOptions]
+  max_open_files=42
+  create_if_missing=true
+  wal_dir=
+
+[CFOptions "default"]
+  comparator=leveldb.BytewiseComparator
+  merge_operator=nullptr
+  table_factory=BlockBasedTable
+  write_buffer_size=1048576
+
+[TableOptions/BlockBasedTable "default"]
+  block_size=8192
+
+[CFOptions "guide"]
+  merge_operator=UInt64AddOperator
+  compression_per_level=kNoCompression:kSnappyCompression
+`
+
+func TestParseOptionsFile(t *testing.T) {
+	parsed, err := parseOptionsFile(strings.NewReader(testOptionsFile))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, parsed.db.String(), "create_if_missing=true;max_open_files=42;wal_dir=;")
+	ensure.DeepEqual(t, len(parsed.cfs), 2)
+	ensure.DeepEqual(t, parsed.cfs[0].name, "default")
+	ensure.DeepEqual(t, parsed.cfs[0].String(),
+		"comparator=leveldb.BytewiseComparator;write_buffer_size=1048576;block_based_table_factory={block_size=8192;};")
+	ensure.DeepEqual(t, parsed.cfs[1].name, "guide")
+	ensure.DeepEqual(t, parsed.cfs[1].options.get("merge_operator", ""), "UInt64AddOperator")
+	ensure.DeepEqual(t, parsed.cfs[1].options.get("comparator", bytewiseComparatorName), bytewiseComparatorName)
+
+	_, err = parseOptionsFile(strings.NewReader("max_open_files=42\n"))
+	ensure.NotNil(t, err)
+	_, err = parseOptionsFile(strings.NewReader("[Unknown]\n"))
+	ensure.NotNil(t, err)
+}
+
+func TestLoadOptionsFromFile(t *testing.T) {
+	db, _, cleanup := newTestDBCF(t, "TestLoadOptionsFromFile")
+	path := db.Name()
+	cleanup()
+
+	file, err := latestOptionsFile(path)
+	ensure.Nil(t, err)
+	loaded, err := LoadOptionsFromFile(file)
+	ensure.Nil(t, err)
+	defer loaded.Destroy()
+	ensure.DeepEqual(t, loaded.ColumnFamilyNames, []string{"default", "guide"})
+
+	ensure.Nil(t, CheckComparatorsAndMergeOperators(path, loaded.ColumnFamilyNames, loaded.ColumnFamilyOptions))
+	err = CheckComparatorsAndMergeOperators(path, loaded.ColumnFamilyNames[:1], loaded.ColumnFamilyOptions[:1])
+	ensure.StringContains(t, err.Error(), `column family "guide"`)
+
+	reversed := NewDefaultOptions()
+	defer reversed.Destroy()
+	reversed.SetComparator(NewReverseBytewiseComparator())
+	err = CheckComparatorsAndMergeOperators(path, loaded.ColumnFamilyNames, []*Options{loaded.ColumnFamilyOptions[0], reversed})
+	ensure.StringContains(t, err.Error(), "comparator")
+
+	db2, cfh2, err := OpenDbColumnFamilies(loaded.DBOptions, path, loaded.ColumnFamilyNames, loaded.ColumnFamilyOptions)
+	ensure.Nil(t, err)
+	for _, cf := range cfh2 {
+		cf.Destroy()
+	}
+	db2.Close()
+}
+
+func TestCheckComparatorsAndMergeOperatorsUnknownComparator(t *testing.T) {
+	db := newTestDB(t, "TestCheckComparatorsAndMergeOperatorsUnknownComparator", func(opts *Options) {
+		opts.SetComparator(NewReverseBytewiseComparator())
+	})
+	path := db.Name()
+	db.Close()
+	defer os.RemoveAll(path)
+
+	// the comparator set by the string is unknown, so it isn't checked
+	parsed, err := GetOptionsFromString(nil, "write_buffer_size=1048576")
+	ensure.Nil(t, err)
+	defer parsed.Destroy()
+	ensure.Nil(t, CheckComparatorsAndMergeOperators(path, []string{"default"}, []*Options{parsed}))
+
+	defaults := NewDefaultOptions()
+	defer defaults.Destroy()
+	err = CheckComparatorsAndMergeOperators(path, []string{"default"}, []*Options{defaults})
+	ensure.StringContains(t, err.Error(), `Invalid argument: column family "default" was created with comparator`)
+}
@@ -0,0 +1,65 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+// LoadLatestOptions loads the options of the database at path from its latest
+// OPTIONS file. env is used to read the file, the default environment if nil,
+// and cache, if not nil, is set as the block cache of the column families
+// using block based tables.
+func LoadLatestOptions(path string, env *Env, cache *Cache) (*LoadedOptions, error) {
+	if env == nil {
+		env = NewDefaultEnv()
+		defer env.Destroy()
+	}
+	var cCache *C.rocksdb_cache_t
+	if cache != nil {
+		cCache = cache.c
+	}
+
+	var (
+		cErr    *C.char
+		cPath   = C.CString(path)
+		cDBOpts *C.rocksdb_options_t
+		cLen    C.size_t
+		cNames  **C.char
+		cOpts   **C.rocksdb_options_t
+	)
+	defer C.free(unsafe.Pointer(cPath))
+
+	C.rocksdb_load_latest_options(cPath, env.c, C.bool(0), cCache, &cDBOpts, &cLen, &cNames, &cOpts, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return nil, errors.New(C.GoString(cErr))
+	}
+	defer C.rocksdb_load_latest_options_destroy(cDBOpts, cNames, cOpts, cLen)
+
+	names := (*[1 << 30]*C.char)(unsafe.Pointer(cNames))[:int(cLen):int(cLen)]
+	opts := (*[1 << 30]*C.rocksdb_options_t)(unsafe.Pointer(cOpts))[:int(cLen):int(cLen)]
+
+	loaded := &LoadedOptions{
+		DBOptions:           NewNativeOptions(C.rocksdb_options_create_copy(cDBOpts)),
+		ColumnFamilyNames:   make([]string, len(names)),
+		ColumnFamilyOptions: make([]*Options, len(opts)),
+	}
+	for i := range names {
+		loaded.ColumnFamilyNames[i] = C.GoString(names[i])
+		loaded.ColumnFamilyOptions[i] = NewNativeOptions(C.rocksdb_options_create_copy(opts[i]))
+	}
+
+	// the comparator and merge operator names are only found in the file
+	if file, err := latestOptionsFile(path); err == nil {
+		if parsed, err := readOptionsFile(file); err == nil {
+			parsed.recordNames(loaded)
+		}
+	}
+	return loaded, nil
+}
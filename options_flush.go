@@ -16,7 +16,9 @@ func NewDefaultFlushOptions() *FlushOptions {
 
 // NewNativeFlushOptions creates a FlushOptions object.
 func NewNativeFlushOptions(c *C.rocksdb_flushoptions_t) *FlushOptions {
-	return &FlushOptions{c}
+	opts := &FlushOptions{c}
+	trackAlloc("FlushOptions", opts)
+	return opts
 }
 
 // SetWait specify if the flush will wait until the flush is done.
@@ -27,6 +29,7 @@ func (opts *FlushOptions) SetWait(value bool) {
 
 // Destroy deallocates the FlushOptions object.
 func (opts *FlushOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_flushoptions_destroy(opts.c)
 	opts.c = nil
 }
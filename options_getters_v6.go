@@ -0,0 +1,590 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GetCreateIfMissing returns create_if_missing, see SetCreateIfMissing.
+func (opts *Options) GetCreateIfMissing() bool {
+	return C.rocksdb_options_get_create_if_missing(opts.c) != 0
+}
+
+// GetErrorIfExists returns error_if_exists, see SetErrorIfExists.
+func (opts *Options) GetErrorIfExists() bool {
+	return C.rocksdb_options_get_error_if_exists(opts.c) != 0
+}
+
+// GetParanoidChecks returns paranoid_checks, see SetParanoidChecks.
+func (opts *Options) GetParanoidChecks() bool {
+	return C.rocksdb_options_get_paranoid_checks(opts.c) != 0
+}
+
+// GetInfoLogLevel returns info_log_level, see SetInfoLogLevel.
+func (opts *Options) GetInfoLogLevel() InfoLogLevel {
+	return InfoLogLevel(C.rocksdb_options_get_info_log_level(opts.c))
+}
+
+// GetAllowConcurrentMemtableWrites returns allow_concurrent_memtable_write, see SetAllowConcurrentMemtableWrites.
+func (opts *Options) GetAllowConcurrentMemtableWrites() bool {
+	return C.rocksdb_options_get_allow_concurrent_memtable_write(opts.c) != 0
+}
+
+// GetEnableWriteThreadAdaptiveYield returns enable_write_thread_adaptive_yield, see SetEnableWriteThreadAdaptiveYield.
+func (opts *Options) GetEnableWriteThreadAdaptiveYield() bool {
+	return C.rocksdb_options_get_enable_write_thread_adaptive_yield(opts.c) != 0
+}
+
+// GetWriteBufferSize returns write_buffer_size, see SetWriteBufferSize.
+func (opts *Options) GetWriteBufferSize() int {
+	return int(C.rocksdb_options_get_write_buffer_size(opts.c))
+}
+
+// GetMaxWriteBufferNumber returns max_write_buffer_number, see SetMaxWriteBufferNumber.
+func (opts *Options) GetMaxWriteBufferNumber() int {
+	return int(C.rocksdb_options_get_max_write_buffer_number(opts.c))
+}
+
+// GetMinWriteBufferNumberToMerge returns min_write_buffer_number_to_merge, see SetMinWriteBufferNumberToMerge.
+func (opts *Options) GetMinWriteBufferNumberToMerge() int {
+	return int(C.rocksdb_options_get_min_write_buffer_number_to_merge(opts.c))
+}
+
+// GetMaxOpenFiles returns max_open_files, see SetMaxOpenFiles.
+func (opts *Options) GetMaxOpenFiles() int {
+	return int(C.rocksdb_options_get_max_open_files(opts.c))
+}
+
+// GetMaxFileOpeningThreads returns max_file_opening_threads, see SetMaxFileOpeningThreads.
+func (opts *Options) GetMaxFileOpeningThreads() int {
+	return int(C.rocksdb_options_get_max_file_opening_threads(opts.c))
+}
+
+// GetMaxTotalWalSize returns max_total_wal_size, see SetMaxTotalWalSize.
+func (opts *Options) GetMaxTotalWalSize() uint64 {
+	return uint64(C.rocksdb_options_get_max_total_wal_size(opts.c))
+}
+
+// GetCompression returns compression, see SetCompression.
+func (opts *Options) GetCompression() CompressionType {
+	return CompressionType(C.rocksdb_options_get_compression(opts.c))
+}
+
+// GetNumLevels returns num_levels, see SetNumLevels.
+func (opts *Options) GetNumLevels() int {
+	return int(C.rocksdb_options_get_num_levels(opts.c))
+}
+
+// GetLevel0FileNumCompactionTrigger returns level0_file_num_compaction_trigger, see SetLevel0FileNumCompactionTrigger.
+func (opts *Options) GetLevel0FileNumCompactionTrigger() int {
+	return int(C.rocksdb_options_get_level0_file_num_compaction_trigger(opts.c))
+}
+
+// GetLevel0SlowdownWritesTrigger returns level0_slowdown_writes_trigger, see SetLevel0SlowdownWritesTrigger.
+func (opts *Options) GetLevel0SlowdownWritesTrigger() int {
+	return int(C.rocksdb_options_get_level0_slowdown_writes_trigger(opts.c))
+}
+
+// GetLevel0StopWritesTrigger returns level0_stop_writes_trigger, see SetLevel0StopWritesTrigger.
+func (opts *Options) GetLevel0StopWritesTrigger() int {
+	return int(C.rocksdb_options_get_level0_stop_writes_trigger(opts.c))
+}
+
+// GetTargetFileSizeBase returns target_file_size_base, see SetTargetFileSizeBase.
+func (opts *Options) GetTargetFileSizeBase() uint64 {
+	return uint64(C.rocksdb_options_get_target_file_size_base(opts.c))
+}
+
+// GetTargetFileSizeMultiplier returns target_file_size_multiplier, see SetTargetFileSizeMultiplier.
+func (opts *Options) GetTargetFileSizeMultiplier() int {
+	return int(C.rocksdb_options_get_target_file_size_multiplier(opts.c))
+}
+
+// GetMaxBytesForLevelBase returns max_bytes_for_level_base, see SetMaxBytesForLevelBase.
+func (opts *Options) GetMaxBytesForLevelBase() uint64 {
+	return uint64(C.rocksdb_options_get_max_bytes_for_level_base(opts.c))
+}
+
+// GetMaxBytesForLevelMultiplier returns max_bytes_for_level_multiplier, see SetMaxBytesForLevelMultiplier.
+func (opts *Options) GetMaxBytesForLevelMultiplier() float64 {
+	return float64(C.rocksdb_options_get_max_bytes_for_level_multiplier(opts.c))
+}
+
+// GetLevelCompactionDynamicLevelBytes returns level_compaction_dynamic_level_bytes, see SetLevelCompactionDynamicLevelBytes.
+func (opts *Options) GetLevelCompactionDynamicLevelBytes() bool {
+	return C.rocksdb_options_get_level_compaction_dynamic_level_bytes(opts.c) != 0
+}
+
+// GetMaxCompactionBytes returns max_compaction_bytes, see SetMaxCompactionBytes.
+func (opts *Options) GetMaxCompactionBytes() uint64 {
+	return uint64(C.rocksdb_options_get_max_compaction_bytes(opts.c))
+}
+
+// GetSoftPendingCompactionBytesLimit returns soft_pending_compaction_bytes_limit, see SetSoftPendingCompactionBytesLimit.
+func (opts *Options) GetSoftPendingCompactionBytesLimit() uint64 {
+	return uint64(C.rocksdb_options_get_soft_pending_compaction_bytes_limit(opts.c))
+}
+
+// GetHardPendingCompactionBytesLimit returns hard_pending_compaction_bytes_limit, see SetHardPendingCompactionBytesLimit.
+func (opts *Options) GetHardPendingCompactionBytesLimit() uint64 {
+	return uint64(C.rocksdb_options_get_hard_pending_compaction_bytes_limit(opts.c))
+}
+
+// GetUseFsync returns use_fsync, see SetUseFsync.
+func (opts *Options) GetUseFsync() bool {
+	return C.rocksdb_options_get_use_fsync(opts.c) != 0
+}
+
+// GetDeleteObsoleteFilesPeriodMicros returns delete_obsolete_files_period_micros, see SetDeleteObsoleteFilesPeriodMicros.
+func (opts *Options) GetDeleteObsoleteFilesPeriodMicros() uint64 {
+	return uint64(C.rocksdb_options_get_delete_obsolete_files_period_micros(opts.c))
+}
+
+// GetMaxBackgroundCompactions returns max_background_compactions, see SetMaxBackgroundCompactions.
+func (opts *Options) GetMaxBackgroundCompactions() int {
+	return int(C.rocksdb_options_get_max_background_compactions(opts.c))
+}
+
+// GetMaxBackgroundFlushes returns max_background_flushes, see SetMaxBackgroundFlushes.
+func (opts *Options) GetMaxBackgroundFlushes() int {
+	return int(C.rocksdb_options_get_max_background_flushes(opts.c))
+}
+
+// GetMaxLogFileSize returns max_log_file_size, see SetMaxLogFileSize.
+func (opts *Options) GetMaxLogFileSize() int {
+	return int(C.rocksdb_options_get_max_log_file_size(opts.c))
+}
+
+// GetLogFileTimeToRoll returns log_file_time_to_roll, see SetLogFileTimeToRoll.
+func (opts *Options) GetLogFileTimeToRoll() int {
+	return int(C.rocksdb_options_get_log_file_time_to_roll(opts.c))
+}
+
+// GetKeepLogFileNum returns keep_log_file_num, see SetKeepLogFileNum.
+func (opts *Options) GetKeepLogFileNum() int {
+	return int(C.rocksdb_options_get_keep_log_file_num(opts.c))
+}
+
+// GetRecycleLogFileNum returns recycle_log_file_num, see SetRecycleLogFileNum.
+func (opts *Options) GetRecycleLogFileNum() int {
+	return int(C.rocksdb_options_get_recycle_log_file_num(opts.c))
+}
+
+// GetMaxManifestFileSize returns max_manifest_file_size, see SetMaxManifestFileSize.
+func (opts *Options) GetMaxManifestFileSize() uint64 {
+	return uint64(C.rocksdb_options_get_max_manifest_file_size(opts.c))
+}
+
+// GetTableCacheNumshardbits returns table_cache_numshardbits, see SetTableCacheNumshardbits.
+func (opts *Options) GetTableCacheNumshardbits() int {
+	return int(C.rocksdb_options_get_table_cache_numshardbits(opts.c))
+}
+
+// GetArenaBlockSize returns arena_block_size, see SetArenaBlockSize.
+func (opts *Options) GetArenaBlockSize() int {
+	return int(C.rocksdb_options_get_arena_block_size(opts.c))
+}
+
+// GetDisableAutoCompactions returns disable_auto_compactions, see SetDisableAutoCompactions.
+func (opts *Options) GetDisableAutoCompactions() bool {
+	return C.rocksdb_options_get_disable_auto_compactions(opts.c) != 0
+}
+
+// GetWALTtlSeconds returns WAL_ttl_seconds, see SetWALTtlSeconds.
+func (opts *Options) GetWALTtlSeconds() uint64 {
+	return uint64(C.rocksdb_options_get_WAL_ttl_seconds(opts.c))
+}
+
+// GetWalSizeLimitMb returns WAL_size_limit_MB, see SetWalSizeLimitMb.
+func (opts *Options) GetWalSizeLimitMb() uint64 {
+	return uint64(C.rocksdb_options_get_WAL_size_limit_MB(opts.c))
+}
+
+// GetEnablePipelinedWrite returns enable_pipelined_write, see SetEnablePipelinedWrite.
+func (opts *Options) GetEnablePipelinedWrite() bool {
+	return C.rocksdb_options_get_enable_pipelined_write(opts.c) != 0
+}
+
+// GetMaxSubcompactions returns max_subcompactions, see SetMaxSubcompactions.
+func (opts *Options) GetMaxSubcompactions() uint {
+	return uint(C.rocksdb_options_get_max_subcompactions(opts.c))
+}
+
+// GetMaxBackgroundJobs returns max_background_jobs, see SetMaxBackgroundJobs.
+func (opts *Options) GetMaxBackgroundJobs() int {
+	return int(C.rocksdb_options_get_max_background_jobs(opts.c))
+}
+
+// GetManifestPreallocationSize returns manifest_preallocation_size, see SetManifestPreallocationSize.
+func (opts *Options) GetManifestPreallocationSize() int {
+	return int(C.rocksdb_options_get_manifest_preallocation_size(opts.c))
+}
+
+// GetAllowMmapReads returns allow_mmap_reads, see SetAllowMmapReads.
+func (opts *Options) GetAllowMmapReads() bool {
+	return C.rocksdb_options_get_allow_mmap_reads(opts.c) != 0
+}
+
+// GetAllowMmapWrites returns allow_mmap_writes, see SetAllowMmapWrites.
+func (opts *Options) GetAllowMmapWrites() bool {
+	return C.rocksdb_options_get_allow_mmap_writes(opts.c) != 0
+}
+
+// GetUseDirectReads returns use_direct_reads, see SetUseDirectReads.
+func (opts *Options) GetUseDirectReads() bool {
+	return C.rocksdb_options_get_use_direct_reads(opts.c) != 0
+}
+
+// GetUseDirectIOForFlushAndCompaction returns use_direct_io_for_flush_and_compaction, see SetUseDirectIOForFlushAndCompaction.
+func (opts *Options) GetUseDirectIOForFlushAndCompaction() bool {
+	return C.rocksdb_options_get_use_direct_io_for_flush_and_compaction(opts.c) != 0
+}
+
+// GetIsFdCloseOnExec returns is_fd_close_on_exec, see SetIsFdCloseOnExec.
+func (opts *Options) GetIsFdCloseOnExec() bool {
+	return C.rocksdb_options_get_is_fd_close_on_exec(opts.c) != 0
+}
+
+// GetStatsDumpPeriodSec returns stats_dump_period_sec, see SetStatsDumpPeriodSec.
+func (opts *Options) GetStatsDumpPeriodSec() uint {
+	return uint(C.rocksdb_options_get_stats_dump_period_sec(opts.c))
+}
+
+// GetAdviseRandomOnOpen returns advise_random_on_open, see SetAdviseRandomOnOpen.
+func (opts *Options) GetAdviseRandomOnOpen() bool {
+	return C.rocksdb_options_get_advise_random_on_open(opts.c) != 0
+}
+
+// GetDbWriteBufferSize returns db_write_buffer_size, see SetDbWriteBufferSize.
+func (opts *Options) GetDbWriteBufferSize() int {
+	return int(C.rocksdb_options_get_db_write_buffer_size(opts.c))
+}
+
+// GetAccessHintOnCompactionStart returns access_hint_on_compaction_start, see SetAccessHintOnCompactionStart.
+func (opts *Options) GetAccessHintOnCompactionStart() CompactionAccessPattern {
+	return CompactionAccessPattern(C.rocksdb_options_get_access_hint_on_compaction_start(opts.c))
+}
+
+// GetUseAdaptiveMutex returns use_adaptive_mutex, see SetUseAdaptiveMutex.
+func (opts *Options) GetUseAdaptiveMutex() bool {
+	return C.rocksdb_options_get_use_adaptive_mutex(opts.c) != 0
+}
+
+// GetBytesPerSync returns bytes_per_sync, see SetBytesPerSync.
+func (opts *Options) GetBytesPerSync() uint64 {
+	return uint64(C.rocksdb_options_get_bytes_per_sync(opts.c))
+}
+
+// GetWalBytesPerSync returns wal_bytes_per_sync, see SetWalBytesPerSync.
+func (opts *Options) GetWalBytesPerSync() uint64 {
+	return uint64(C.rocksdb_options_get_wal_bytes_per_sync(opts.c))
+}
+
+// GetWritableFileMaxBufferSize returns writable_file_max_buffer_size, see SetWritableFileMaxBufferSize.
+func (opts *Options) GetWritableFileMaxBufferSize() uint64 {
+	return uint64(C.rocksdb_options_get_writable_file_max_buffer_size(opts.c))
+}
+
+// GetCompactionStyle returns compaction_style, see SetCompactionStyle.
+func (opts *Options) GetCompactionStyle() CompactionStyle {
+	return CompactionStyle(C.rocksdb_options_get_compaction_style(opts.c))
+}
+
+// GetMaxSequentialSkipInIterations returns max_sequential_skip_in_iterations, see SetMaxSequentialSkipInIterations.
+func (opts *Options) GetMaxSequentialSkipInIterations() uint64 {
+	return uint64(C.rocksdb_options_get_max_sequential_skip_in_iterations(opts.c))
+}
+
+// GetInplaceUpdateSupport returns inplace_update_support, see SetInplaceUpdateSupport.
+func (opts *Options) GetInplaceUpdateSupport() bool {
+	return C.rocksdb_options_get_inplace_update_support(opts.c) != 0
+}
+
+// GetInplaceUpdateNumLocks returns inplace_update_num_locks, see SetInplaceUpdateNumLocks.
+func (opts *Options) GetInplaceUpdateNumLocks() int {
+	return int(C.rocksdb_options_get_inplace_update_num_locks(opts.c))
+}
+
+// GetReportBgIoStats returns report_bg_io_stats, see SetReportBgIoStats.
+func (opts *Options) GetReportBgIoStats() bool {
+	return C.rocksdb_options_get_report_bg_io_stats(opts.c) != 0
+}
+
+// GetMemtableHugePageSize returns memtable_huge_page_size, see SetMemtableHugePageSize.
+func (opts *Options) GetMemtableHugePageSize() int {
+	return int(C.rocksdb_options_get_memtable_huge_page_size(opts.c))
+}
+
+// GetBloomLocality returns bloom_locality, see SetBloomLocality.
+func (opts *Options) GetBloomLocality() uint32 {
+	return uint32(C.rocksdb_options_get_bloom_locality(opts.c))
+}
+
+// GetMaxSuccessiveMerges returns max_successive_merges, see SetMaxSuccessiveMerges.
+func (opts *Options) GetMaxSuccessiveMerges() int {
+	return int(C.rocksdb_options_get_max_successive_merges(opts.c))
+}
+
+// GetMemtablePrefixBloomSizeRatio returns memtable_prefix_bloom_size_ratio, see SetMemtablePrefixBloomSizeRatio.
+func (opts *Options) GetMemtablePrefixBloomSizeRatio() float64 {
+	return float64(C.rocksdb_options_get_memtable_prefix_bloom_size_ratio(opts.c))
+}
+
+// GetCreateIfMissingColumnFamilies returns create_missing_column_families, see SetCreateIfMissingColumnFamilies.
+func (opts *Options) GetCreateIfMissingColumnFamilies() bool {
+	return C.rocksdb_options_get_create_missing_column_families(opts.c) != 0
+}
+
+// GetAllowIngestBehind returns allow_ingest_behind, see SetAllowIngestBehind.
+func (opts *Options) GetAllowIngestBehind() bool {
+	return C.rocksdb_options_get_allow_ingest_behind(opts.c) != 0
+}
+
+// GetOptimizeFiltersForHits returns optimize_filters_for_hits, see SetOptimizeFiltersForHits.
+func (opts *Options) GetOptimizeFiltersForHits() bool {
+	return C.rocksdb_options_get_optimize_filters_for_hits(opts.c) != 0
+}
+
+// GetSkipStatsUpdateOnDbOpen returns skip_stats_update_on_db_open, see SetSkipStatsUpdateOnDbOpen.
+func (opts *Options) GetSkipStatsUpdateOnDbOpen() bool {
+	return C.rocksdb_options_get_skip_stats_update_on_db_open(opts.c) != 0
+}
+
+// GetAtomicFlush returns atomic_flush, see SetAtomicFlush.
+func (opts *Options) GetAtomicFlush() bool {
+	return C.rocksdb_options_get_atomic_flush(opts.c) != 0
+}
+
+// GetBottommostCompression returns bottommost_compression, see SetBottommostCompression.
+func (opts *Options) GetBottommostCompression() CompressionType {
+	return CompressionType(C.rocksdb_options_get_bottommost_compression(opts.c))
+}
+
+// GetMaxWriteBufferSizeToMaintain returns max_write_buffer_size_to_maintain, see SetMaxWriteBufferSizeToMaintain.
+func (opts *Options) GetMaxWriteBufferSizeToMaintain() int64 {
+	return int64(C.rocksdb_options_get_max_write_buffer_size_to_maintain(opts.c))
+}
+
+// GetSkipCheckingSstFileSizesOnDbOpen returns skip_checking_sst_file_sizes_on_db_open, see SetSkipCheckingSstFileSizesOnDbOpen.
+func (opts *Options) GetSkipCheckingSstFileSizesOnDbOpen() bool {
+	return C.rocksdb_options_get_skip_checking_sst_file_sizes_on_db_open(opts.c) != 0
+}
+
+// GetStatsPersistPeriodSec returns stats_persist_period_sec, see SetStatsPersistPeriodSec.
+func (opts *Options) GetStatsPersistPeriodSec() int {
+	return int(C.rocksdb_options_get_stats_persist_period_sec(opts.c))
+}
+
+// GetUnorderedWrite returns unordered_write, see SetUnorderedWrite.
+func (opts *Options) GetUnorderedWrite() bool {
+	return C.rocksdb_options_get_unordered_write(opts.c) != 0
+}
+
+// String renders the options like the sections of an OPTIONS file, one
+// option per line in name order, so that two Options can be diffed. Only the
+// options that can be read back are rendered; the column family options are
+// rendered in a CFOptions "default" section. The C API can't read back
+// db_log_dir, wal_dir, compression_per_level and
+// max_bytes_for_level_multiplier_additional, so they are only rendered once
+// set through their setters, and left out of the Options loaded or parsed by
+// RocksDB.
+func (opts *Options) String() string {
+	dbOptions := map[string]string{
+		"create_if_missing":                       strconv.FormatBool(opts.GetCreateIfMissing()),
+		"error_if_exists":                         strconv.FormatBool(opts.GetErrorIfExists()),
+		"paranoid_checks":                         strconv.FormatBool(opts.GetParanoidChecks()),
+		"info_log_level":                          infoLogLevelName(opts.GetInfoLogLevel()),
+		"allow_concurrent_memtable_write":         strconv.FormatBool(opts.GetAllowConcurrentMemtableWrites()),
+		"enable_write_thread_adaptive_yield":      strconv.FormatBool(opts.GetEnableWriteThreadAdaptiveYield()),
+		"max_open_files":                          strconv.Itoa(opts.GetMaxOpenFiles()),
+		"max_file_opening_threads":                strconv.Itoa(opts.GetMaxFileOpeningThreads()),
+		"max_total_wal_size":                      strconv.FormatUint(opts.GetMaxTotalWalSize(), 10),
+		"use_fsync":                               strconv.FormatBool(opts.GetUseFsync()),
+		"delete_obsolete_files_period_micros":     strconv.FormatUint(opts.GetDeleteObsoleteFilesPeriodMicros(), 10),
+		"max_background_compactions":              strconv.Itoa(opts.GetMaxBackgroundCompactions()),
+		"max_background_flushes":                  strconv.Itoa(opts.GetMaxBackgroundFlushes()),
+		"max_log_file_size":                       strconv.Itoa(opts.GetMaxLogFileSize()),
+		"log_file_time_to_roll":                   strconv.Itoa(opts.GetLogFileTimeToRoll()),
+		"keep_log_file_num":                       strconv.Itoa(opts.GetKeepLogFileNum()),
+		"recycle_log_file_num":                    strconv.Itoa(opts.GetRecycleLogFileNum()),
+		"max_manifest_file_size":                  strconv.FormatUint(opts.GetMaxManifestFileSize(), 10),
+		"table_cache_numshardbits":                strconv.Itoa(opts.GetTableCacheNumshardbits()),
+		"WAL_ttl_seconds":                         strconv.FormatUint(opts.GetWALTtlSeconds(), 10),
+		"WAL_size_limit_MB":                       strconv.FormatUint(opts.GetWalSizeLimitMb(), 10),
+		"enable_pipelined_write":                  strconv.FormatBool(opts.GetEnablePipelinedWrite()),
+		"max_subcompactions":                      strconv.FormatUint(uint64(opts.GetMaxSubcompactions()), 10),
+		"max_background_jobs":                     strconv.Itoa(opts.GetMaxBackgroundJobs()),
+		"manifest_preallocation_size":             strconv.Itoa(opts.GetManifestPreallocationSize()),
+		"allow_mmap_reads":                        strconv.FormatBool(opts.GetAllowMmapReads()),
+		"allow_mmap_writes":                       strconv.FormatBool(opts.GetAllowMmapWrites()),
+		"use_direct_reads":                        strconv.FormatBool(opts.GetUseDirectReads()),
+		"use_direct_io_for_flush_and_compaction":  strconv.FormatBool(opts.GetUseDirectIOForFlushAndCompaction()),
+		"is_fd_close_on_exec":                     strconv.FormatBool(opts.GetIsFdCloseOnExec()),
+		"stats_dump_period_sec":                   strconv.FormatUint(uint64(opts.GetStatsDumpPeriodSec()), 10),
+		"advise_random_on_open":                   strconv.FormatBool(opts.GetAdviseRandomOnOpen()),
+		"db_write_buffer_size":                    strconv.Itoa(opts.GetDbWriteBufferSize()),
+		"access_hint_on_compaction_start":         compactionAccessPatternName(opts.GetAccessHintOnCompactionStart()),
+		"use_adaptive_mutex":                      strconv.FormatBool(opts.GetUseAdaptiveMutex()),
+		"bytes_per_sync":                          strconv.FormatUint(opts.GetBytesPerSync(), 10),
+		"wal_bytes_per_sync":                      strconv.FormatUint(opts.GetWalBytesPerSync(), 10),
+		"writable_file_max_buffer_size":           strconv.FormatUint(opts.GetWritableFileMaxBufferSize(), 10),
+		"create_missing_column_families":          strconv.FormatBool(opts.GetCreateIfMissingColumnFamilies()),
+		"allow_ingest_behind":                     strconv.FormatBool(opts.GetAllowIngestBehind()),
+		"skip_stats_update_on_db_open":            strconv.FormatBool(opts.GetSkipStatsUpdateOnDbOpen()),
+		"atomic_flush":                            strconv.FormatBool(opts.GetAtomicFlush()),
+		"skip_checking_sst_file_sizes_on_db_open": strconv.FormatBool(opts.GetSkipCheckingSstFileSizesOnDbOpen()),
+		"stats_persist_period_sec":                strconv.Itoa(opts.GetStatsPersistPeriodSec()),
+		"unordered_write":                         strconv.FormatBool(opts.GetUnorderedWrite()),
+	}
+	cfOptions := map[string]string{
+		"write_buffer_size":                    strconv.Itoa(opts.GetWriteBufferSize()),
+		"max_write_buffer_number":              strconv.Itoa(opts.GetMaxWriteBufferNumber()),
+		"min_write_buffer_number_to_merge":     strconv.Itoa(opts.GetMinWriteBufferNumberToMerge()),
+		"compression":                          compressionTypeName(opts.GetCompression()),
+		"num_levels":                           strconv.Itoa(opts.GetNumLevels()),
+		"level0_file_num_compaction_trigger":   strconv.Itoa(opts.GetLevel0FileNumCompactionTrigger()),
+		"level0_slowdown_writes_trigger":       strconv.Itoa(opts.GetLevel0SlowdownWritesTrigger()),
+		"level0_stop_writes_trigger":           strconv.Itoa(opts.GetLevel0StopWritesTrigger()),
+		"target_file_size_base":                strconv.FormatUint(opts.GetTargetFileSizeBase(), 10),
+		"target_file_size_multiplier":          strconv.Itoa(opts.GetTargetFileSizeMultiplier()),
+		"max_bytes_for_level_base":             strconv.FormatUint(opts.GetMaxBytesForLevelBase(), 10),
+		"max_bytes_for_level_multiplier":       strconv.FormatFloat(opts.GetMaxBytesForLevelMultiplier(), 'f', -1, 64),
+		"level_compaction_dynamic_level_bytes": strconv.FormatBool(opts.GetLevelCompactionDynamicLevelBytes()),
+		"max_compaction_bytes":                 strconv.FormatUint(opts.GetMaxCompactionBytes(), 10),
+		"soft_pending_compaction_bytes_limit":  strconv.FormatUint(opts.GetSoftPendingCompactionBytesLimit(), 10),
+		"hard_pending_compaction_bytes_limit":  strconv.FormatUint(opts.GetHardPendingCompactionBytesLimit(), 10),
+		"arena_block_size":                     strconv.Itoa(opts.GetArenaBlockSize()),
+		"disable_auto_compactions":             strconv.FormatBool(opts.GetDisableAutoCompactions()),
+		"compaction_style":                     compactionStyleName(opts.GetCompactionStyle()),
+		"max_sequential_skip_in_iterations":    strconv.FormatUint(opts.GetMaxSequentialSkipInIterations(), 10),
+		"inplace_update_support":               strconv.FormatBool(opts.GetInplaceUpdateSupport()),
+		"inplace_update_num_locks":             strconv.Itoa(opts.GetInplaceUpdateNumLocks()),
+		"report_bg_io_stats":                   strconv.FormatBool(opts.GetReportBgIoStats()),
+		"memtable_huge_page_size":              strconv.Itoa(opts.GetMemtableHugePageSize()),
+		"bloom_locality":                       strconv.FormatUint(uint64(opts.GetBloomLocality()), 10),
+		"max_successive_merges":                strconv.Itoa(opts.GetMaxSuccessiveMerges()),
+		"memtable_prefix_bloom_size_ratio":     strconv.FormatFloat(opts.GetMemtablePrefixBloomSizeRatio(), 'f', -1, 64),
+		"optimize_filters_for_hits":            strconv.FormatBool(opts.GetOptimizeFiltersForHits()),
+		"bottommost_compression":               compressionTypeName(opts.GetBottommostCompression()),
+		"max_write_buffer_size_to_maintain":    strconv.FormatInt(opts.GetMaxWriteBufferSizeToMaintain(), 10),
+	}
+	if opts.dbLogDir != nil {
+		dbOptions["db_log_dir"] = *opts.dbLogDir
+	}
+	if opts.walDir != nil {
+		dbOptions["wal_dir"] = *opts.walDir
+	}
+	if opts.compressionPerLevel != nil {
+		cfOptions["compression_per_level"] = joinCompressionTypes(opts.compressionPerLevel)
+	}
+	if opts.maxBytesForLevelMultiplierAdditional != nil {
+		cfOptions["max_bytes_for_level_multiplier_additional"] = joinInts(opts.maxBytesForLevelMultiplierAdditional)
+	}
+
+	var b strings.Builder
+	writeOptionsSection(&b, "DBOptions", dbOptions)
+	b.WriteByte('\n')
+	writeOptionsSection(&b, `CFOptions "default"`, cfOptions)
+	return b.String()
+}
+
+func writeOptionsSection(b *strings.Builder, section string, options map[string]string) {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString("[" + section + "]\n")
+	for _, name := range names {
+		b.WriteString("  " + name + "=" + options[name] + "\n")
+	}
+}
+
+func compressionTypeName(value CompressionType) string {
+	switch value {
+	case NoCompression:
+		return "kNoCompression"
+	case SnappyCompression:
+		return "kSnappyCompression"
+	case ZLibCompression:
+		return "kZlibCompression"
+	case Bz2Compression:
+		return "kBZip2Compression"
+	case LZ4Compression:
+		return "kLZ4Compression"
+	case LZ4HCCompression:
+		return "kLZ4HCCompression"
+	case XpressCompression:
+		return "kXpressCompression"
+	case ZSTDCompression:
+		return "kZSTD"
+	case DisableCompressionOption:
+		return "kDisableCompressionOption"
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func compactionStyleName(value CompactionStyle) string {
+	switch value {
+	case LevelCompactionStyle:
+		return "kCompactionStyleLevel"
+	case UniversalCompactionStyle:
+		return "kCompactionStyleUniversal"
+	case FIFOCompactionStyle:
+		return "kCompactionStyleFIFO"
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func infoLogLevelName(value InfoLogLevel) string {
+	switch value {
+	case DebugInfoLogLevel:
+		return "DEBUG_LEVEL"
+	case InfoInfoLogLevel:
+		return "INFO_LEVEL"
+	case WarnInfoLogLevel:
+		return "WARN_LEVEL"
+	case ErrorInfoLogLevel:
+		return "ERROR_LEVEL"
+	case FatalInfoLogLevel:
+		return "FATAL_LEVEL"
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func compactionAccessPatternName(value CompactionAccessPattern) string {
+	switch value {
+	case NoneCompactionAccessPattern:
+		return "NONE"
+	case NormalCompactionAccessPattern:
+		return "NORMAL"
+	case SequentialCompactionAccessPattern:
+		return "SEQUENTIAL"
+	case WillneedCompactionAccessPattern:
+		return "WILLNEED"
+	}
+	return strconv.FormatUint(uint64(value), 10)
+}
+
+func joinCompressionTypes(values []CompressionType) string {
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = compressionTypeName(value)
+	}
+	return strings.Join(names, ":")
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+	return strings.Join(strs, ":")
+}
@@ -15,7 +15,9 @@ func NewDefaultIngestExternalFileOptions() *IngestExternalFileOptions {
 
 // NewNativeIngestExternalFileOptions creates a IngestExternalFileOptions object.
 func NewNativeIngestExternalFileOptions(c *C.rocksdb_ingestexternalfileoptions_t) *IngestExternalFileOptions {
-	return &IngestExternalFileOptions{c: c}
+	opts := &IngestExternalFileOptions{c: c}
+	trackAlloc("IngestExternalFileOptions", opts)
+	return opts
 }
 
 // SetMoveFiles specifies if it should move the files instead of copying them.
@@ -60,6 +62,7 @@ func (opts *IngestExternalFileOptions) SetIngestionBehind(flag bool) {
 
 // Destroy deallocates the IngestExternalFileOptions object.
 func (opts *IngestExternalFileOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_ingestexternalfileoptions_destroy(opts.c)
 	opts.c = nil
 }
@@ -0,0 +1,188 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MutableCFOptions holds the column family options that can be changed while
+// the database is open, for SetMutableCFOptions. A nil field leaves the option
+// unchanged.
+type MutableCFOptions struct {
+	WriteBufferSize                 *uint64
+	MaxWriteBufferNumber            *int
+	ArenaBlockSize                  *uint64
+	DisableAutoCompactions          *bool
+	Level0FileNumCompactionTrigger  *int
+	Level0SlowdownWritesTrigger     *int
+	Level0StopWritesTrigger         *int
+	TargetFileSizeBase              *uint64
+	TargetFileSizeMultiplier        *int
+	MaxBytesForLevelBase            *uint64
+	MaxBytesForLevelMultiplier      *float64
+	MaxCompactionBytes              *uint64
+	SoftPendingCompactionBytesLimit *uint64
+	HardPendingCompactionBytesLimit *uint64
+	MaxSequentialSkipInIterations   *uint64
+	MemtablePrefixBloomSizeRatio    *float64
+	MaxSuccessiveMerges             *uint64
+	InplaceUpdateNumLocks           *uint64
+	ReportBgIoStats                 *bool
+	ParanoidFileChecks              *bool
+	Compression                     *CompressionType
+	BottommostCompression           *CompressionType
+}
+
+// maxMemtablePrefixBloomSizeRatio is the ratio RocksDB sanitizes the higher
+// ones to.
+const maxMemtablePrefixBloomSizeRatio = 0.25
+
+// Validate checks the values of the options and their combinations, returning
+// ConfigErrors if any. Only the options set are checked, as the current values
+// of the others can't be read from the database.
+func (o *MutableCFOptions) Validate() error {
+	var errs ConfigErrors
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Field: field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if o.WriteBufferSize != nil && *o.WriteBufferSize == 0 {
+		fail("write_buffer_size", "must be positive")
+	}
+	if o.MaxWriteBufferNumber != nil && *o.MaxWriteBufferNumber < 2 {
+		fail("max_write_buffer_number", "must be at least 2")
+	}
+	if o.TargetFileSizeMultiplier != nil && *o.TargetFileSizeMultiplier <= 0 {
+		fail("target_file_size_multiplier", "must be positive")
+	}
+	if o.MaxBytesForLevelMultiplier != nil && *o.MaxBytesForLevelMultiplier <= 0 {
+		fail("max_bytes_for_level_multiplier", "must be positive")
+	}
+	if r := o.MemtablePrefixBloomSizeRatio; r != nil && (*r < 0 || *r > maxMemtablePrefixBloomSizeRatio) {
+		fail("memtable_prefix_bloom_size_ratio", "must be between 0 and %g", maxMemtablePrefixBloomSizeRatio)
+	}
+	if o.InplaceUpdateNumLocks != nil && *o.InplaceUpdateNumLocks == 0 {
+		fail("inplace_update_num_locks", "must be positive")
+	}
+
+	for _, trigger := range []struct {
+		name  string
+		value *int
+	}{
+		{"level0_file_num_compaction_trigger", o.Level0FileNumCompactionTrigger},
+		{"level0_slowdown_writes_trigger", o.Level0SlowdownWritesTrigger},
+		{"level0_stop_writes_trigger", o.Level0StopWritesTrigger},
+	} {
+		if trigger.value != nil && *trigger.value <= 0 {
+			fail(trigger.name, "must be positive")
+		}
+	}
+	if a, b := o.Level0FileNumCompactionTrigger, o.Level0SlowdownWritesTrigger; a != nil && b != nil && *a > *b {
+		fail("level0_slowdown_writes_trigger", "must not be lower than level0_file_num_compaction_trigger")
+	}
+	if a, b := o.Level0SlowdownWritesTrigger, o.Level0StopWritesTrigger; a != nil && b != nil && *a > *b {
+		fail("level0_stop_writes_trigger", "must not be lower than level0_slowdown_writes_trigger")
+	}
+	if a, b := o.SoftPendingCompactionBytesLimit, o.HardPendingCompactionBytesLimit; a != nil && b != nil && *b != 0 && *a > *b {
+		fail("hard_pending_compaction_bytes_limit", "must not be lower than soft_pending_compaction_bytes_limit")
+	}
+
+	for _, compression := range []struct {
+		name  string
+		value *CompressionType
+	}{
+		{"compression", o.Compression},
+		{"bottommost_compression", o.BottommostCompression},
+	} {
+		if compression.value == nil {
+			continue
+		}
+		// the default bottommost compression can be restored
+		if compression.name == "bottommost_compression" && *compression.value == DisableCompressionOption {
+			continue
+		}
+		if *compression.value > ZSTDCompression {
+			fail(compression.name, "unknown compression %d", *compression.value)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// keysValues returns the names and values of the options set, in the format
+// of SetOptionsCF.
+func (o *MutableCFOptions) keysValues() (keys, values []string) {
+	set := func(key, value string) {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	setUint := func(key string, value *uint64) {
+		if value != nil {
+			set(key, strconv.FormatUint(*value, 10))
+		}
+	}
+	setInt := func(key string, value *int) {
+		if value != nil {
+			set(key, strconv.Itoa(*value))
+		}
+	}
+	setFloat := func(key string, value *float64) {
+		if value != nil {
+			set(key, strconv.FormatFloat(*value, 'g', -1, 64))
+		}
+	}
+	setBool := func(key string, value *bool) {
+		if value != nil {
+			set(key, strconv.FormatBool(*value))
+		}
+	}
+	setCompression := func(key string, value *CompressionType) {
+		if value != nil {
+			set(key, compressionTypeName(*value))
+		}
+	}
+
+	setUint("write_buffer_size", o.WriteBufferSize)
+	setInt("max_write_buffer_number", o.MaxWriteBufferNumber)
+	setUint("arena_block_size", o.ArenaBlockSize)
+	setBool("disable_auto_compactions", o.DisableAutoCompactions)
+	setInt("level0_file_num_compaction_trigger", o.Level0FileNumCompactionTrigger)
+	setInt("level0_slowdown_writes_trigger", o.Level0SlowdownWritesTrigger)
+	setInt("level0_stop_writes_trigger", o.Level0StopWritesTrigger)
+	setUint("target_file_size_base", o.TargetFileSizeBase)
+	setInt("target_file_size_multiplier", o.TargetFileSizeMultiplier)
+	setUint("max_bytes_for_level_base", o.MaxBytesForLevelBase)
+	setFloat("max_bytes_for_level_multiplier", o.MaxBytesForLevelMultiplier)
+	setUint("max_compaction_bytes", o.MaxCompactionBytes)
+	setUint("soft_pending_compaction_bytes_limit", o.SoftPendingCompactionBytesLimit)
+	setUint("hard_pending_compaction_bytes_limit", o.HardPendingCompactionBytesLimit)
+	setUint("max_sequential_skip_in_iterations", o.MaxSequentialSkipInIterations)
+	setFloat("memtable_prefix_bloom_size_ratio", o.MemtablePrefixBloomSizeRatio)
+	setUint("max_successive_merges", o.MaxSuccessiveMerges)
+	setUint("inplace_update_num_locks", o.InplaceUpdateNumLocks)
+	setBool("report_bg_io_stats", o.ReportBgIoStats)
+	setBool("paranoid_file_checks", o.ParanoidFileChecks)
+	setCompression("compression", o.Compression)
+	setCompression("bottommost_compression", o.BottommostCompression)
+	return keys, values
+}
+
+// SetMutableCFOptions validates the options and changes them on the column
+// family, or on the default column family if cf is nil, in a single call to
+// SetOptionsCF, so that either all or none of them are applied.
+//
+// There is no SetDBOptions counterpart for the mutable database options,
+// such as max_background_jobs: the C API doesn't expose DB::SetDBOptions.
+func (db *DB) SetMutableCFOptions(cf *ColumnFamilyHandle, opts *MutableCFOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	keys, values := opts.keysValues()
+	return db.SetOptionsCF(cf, keys, values)
+}
@@ -29,6 +29,23 @@ type ReadOptions struct {
 	c                  *C.rocksdb_readoptions_t
 	cIterateLowerBound *C.char
 	cIterateUpperBound *C.char
+	pinData            bool
+	valueSizeSoftLimit uint64
+
+	// the settings the C API has no getters for, kept for Clone.
+	cSnapshot             *C.rocksdb_snapshot_t
+	iterateLowerBoundSize C.size_t
+	iterateUpperBoundSize C.size_t
+	// the settings RocksDB 5 has no getters for, nil unless they were set.
+	verifyChecksums          *bool
+	fillCache                *bool
+	readTier                 *ReadTier
+	tailing                  *bool
+	readaheadSize            *uint64
+	totalOrderSeek           *bool
+	maxSkippableInternalKeys *uint64
+	backgroundPurge          *bool
+	ignoreRangeDeletions     *bool
 }
 
 // NewDefaultReadOptions creates a default ReadOptions object.
@@ -38,7 +55,26 @@ func NewDefaultReadOptions() *ReadOptions {
 
 // NewNativeReadOptions creates a ReadOptions object.
 func NewNativeReadOptions(c *C.rocksdb_readoptions_t) *ReadOptions {
-	return &ReadOptions{c: c}
+	opts := &ReadOptions{c: c}
+	trackAlloc("ReadOptions", opts)
+	return opts
+}
+
+// cloneBounds sets the snapshot and the iterate bounds of opts on clone, the
+// bounds being copied into memory owned by the clone.
+func (opts *ReadOptions) cloneBounds(clone *ReadOptions) {
+	if opts.cSnapshot != nil {
+		clone.cSnapshot = opts.cSnapshot
+		C.rocksdb_readoptions_set_snapshot(clone.c, opts.cSnapshot)
+	}
+	if opts.cIterateLowerBound != nil {
+		clone.SetIterateLowerBound(C.GoBytes(unsafe.Pointer(opts.cIterateLowerBound), C.int(opts.iterateLowerBoundSize)))
+	}
+	if opts.cIterateUpperBound != nil {
+		clone.SetIterateUpperBound(C.GoBytes(unsafe.Pointer(opts.cIterateUpperBound), C.int(opts.iterateUpperBoundSize)))
+	}
+	clone.pinData = opts.pinData
+	clone.valueSizeSoftLimit = opts.valueSizeSoftLimit
 }
 
 // UnsafeGetReadOptions returns the underlying c read options object.
@@ -51,6 +87,7 @@ func (opts *ReadOptions) UnsafeGetReadOptions() unsafe.Pointer {
 // Default: false
 func (opts *ReadOptions) SetVerifyChecksums(value bool) {
 	C.rocksdb_readoptions_set_verify_checksums(opts.c, boolToChar(value))
+	opts.verifyChecksums = &value
 }
 
 // SetFillCache specify whether the "data block"/"index block"/"filter block"
@@ -59,6 +96,7 @@ func (opts *ReadOptions) SetVerifyChecksums(value bool) {
 // Default: true
 func (opts *ReadOptions) SetFillCache(value bool) {
 	C.rocksdb_readoptions_set_fill_cache(opts.c, boolToChar(value))
+	opts.fillCache = &value
 }
 
 // SetSnapshot sets the snapshot which should be used for the read.
@@ -67,6 +105,7 @@ func (opts *ReadOptions) SetFillCache(value bool) {
 // Default: nil
 func (opts *ReadOptions) SetSnapshot(snap *Snapshot) {
 	C.rocksdb_readoptions_set_snapshot(opts.c, snap.c)
+	opts.cSnapshot = snap.c
 }
 
 // SetReadTier specify if this read request should process data that ALREADY
@@ -75,6 +114,7 @@ func (opts *ReadOptions) SetSnapshot(snap *Snapshot) {
 // Default: ReadAllTier
 func (opts *ReadOptions) SetReadTier(value ReadTier) {
 	C.rocksdb_readoptions_set_read_tier(opts.c, C.int(value))
+	opts.readTier = &value
 }
 
 // SetTailing specify if to create a tailing iterator.
@@ -85,6 +125,7 @@ func (opts *ReadOptions) SetReadTier(value ReadTier) {
 // Default: false
 func (opts *ReadOptions) SetTailing(value bool) {
 	C.rocksdb_readoptions_set_tailing(opts.c, boolToChar(value))
+	opts.tailing = &value
 }
 
 // SetIterateUpperBound specifies "iterate_upper_bound", which defines
@@ -105,6 +146,7 @@ func (opts *ReadOptions) SetIterateUpperBound(key []byte) {
 		opts.cIterateUpperBound = cByteSlice(key)
 	}
 	cKeyLen := C.size_t(len(key))
+	opts.iterateUpperBoundSize = cKeyLen
 	C.rocksdb_readoptions_set_iterate_upper_bound(opts.c, opts.cIterateUpperBound, cKeyLen)
 }
 
@@ -126,6 +168,7 @@ func (opts *ReadOptions) SetIterateLowerBound(key []byte) {
 		opts.cIterateLowerBound = cByteSlice(key)
 	}
 	cKeyLen := C.size_t(len(key))
+	opts.iterateLowerBoundSize = cKeyLen
 	C.rocksdb_readoptions_set_iterate_lower_bound(opts.c, opts.cIterateLowerBound, cKeyLen)
 }
 
@@ -138,6 +181,20 @@ func (opts *ReadOptions) SetIterateLowerBound(key []byte) {
 // Default: false
 func (opts *ReadOptions) SetPinData(value bool) {
 	C.rocksdb_readoptions_set_pin_data(opts.c, boolToChar(value))
+	opts.pinData = value
+}
+
+// SetValueSizeSoftLimit sets a soft limit on the cumulative size of the values
+// returned by MultiGetBatch. Once it is exceeded, the remaining keys fail with
+// ErrValueSizeSoftLimitExceeded.
+//
+// This is not RocksDB's value_size_soft_limit, which the C API doesn't expose:
+// the limit is enforced in Go by MultiGetBatch between the chunks of 64 keys
+// it looks up at a time, so up to a chunk of values is still read from the
+// database once the limit is exceeded.
+// Default: 0 (no limit)
+func (opts *ReadOptions) SetValueSizeSoftLimit(limit uint64) {
+	opts.valueSizeSoftLimit = limit
 }
 
 // SetReadaheadSize specifies the value of "readahead_size".
@@ -147,6 +204,7 @@ func (opts *ReadOptions) SetPinData(value bool) {
 // Default: 0
 func (opts *ReadOptions) SetReadaheadSize(value uint64) {
 	C.rocksdb_readoptions_set_readahead_size(opts.c, C.size_t(value))
+	opts.readaheadSize = &value
 }
 
 // SetTotalOrderSeek specifies the value of "total_order_seek".
@@ -159,6 +217,7 @@ func (opts *ReadOptions) SetReadaheadSize(value uint64) {
 // Default: false
 func (opts *ReadOptions) SetTotalOrderSeek(value bool) {
 	C.rocksdb_readoptions_set_total_order_seek(opts.c, boolToChar(value))
+	opts.totalOrderSeek = &value
 }
 
 // SetMaxSkippableInternalKeys specifies the value of "max_skippable_internal_keys".
@@ -168,6 +227,7 @@ func (opts *ReadOptions) SetTotalOrderSeek(value bool) {
 // Default: 0
 func (opts *ReadOptions) SetMaxSkippableInternalKeys(value uint64) {
 	C.rocksdb_readoptions_set_max_skippable_internal_keys(opts.c, C.uint64_t(value))
+	opts.maxSkippableInternalKeys = &value
 }
 
 // SetBackgroundPurgeOnIteratorCleanup specifies the value of "background_purge_on_iterator_cleanup".
@@ -177,6 +237,7 @@ func (opts *ReadOptions) SetMaxSkippableInternalKeys(value uint64) {
 // Default: false
 func (opts *ReadOptions) SetBackgroundPurgeOnIteratorCleanup(value bool) {
 	C.rocksdb_readoptions_set_background_purge_on_iterator_cleanup(opts.c, boolToChar(value))
+	opts.backgroundPurge = &value
 }
 
 // SetIgnoreRangeDeletions specifies the value of "ignore_range_deletions".
@@ -186,10 +247,12 @@ func (opts *ReadOptions) SetBackgroundPurgeOnIteratorCleanup(value bool) {
 // Default: false
 func (opts *ReadOptions) SetIgnoreRangeDeletions(value bool) {
 	C.rocksdb_readoptions_set_ignore_range_deletions(opts.c, boolToChar(value))
+	opts.ignoreRangeDeletions = &value
 }
 
 // Destroy deallocates the ReadOptions object.
 func (opts *ReadOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_readoptions_destroy(opts.c)
 
 	C.free(unsafe.Pointer(opts.cIterateLowerBound))
@@ -0,0 +1,45 @@
+//go:build !v6
+// +build !v6
+
+package gorocksdb
+
+// Clone returns a new ReadOptions with the same settings, the iterate bounds
+// being copied into memory owned by the clone. RocksDB 5 has no getters for
+// the read options, so only the settings set through the setters of opts are
+// copied; the settings of the options created by NewNativeReadOptions keep
+// their default values.
+func (opts *ReadOptions) Clone() *ReadOptions {
+	clone := NewDefaultReadOptions()
+	if opts.verifyChecksums != nil {
+		clone.SetVerifyChecksums(*opts.verifyChecksums)
+	}
+	if opts.fillCache != nil {
+		clone.SetFillCache(*opts.fillCache)
+	}
+	if opts.readTier != nil {
+		clone.SetReadTier(*opts.readTier)
+	}
+	if opts.tailing != nil {
+		clone.SetTailing(*opts.tailing)
+	}
+	if opts.pinData {
+		clone.SetPinData(true)
+	}
+	if opts.readaheadSize != nil {
+		clone.SetReadaheadSize(*opts.readaheadSize)
+	}
+	if opts.totalOrderSeek != nil {
+		clone.SetTotalOrderSeek(*opts.totalOrderSeek)
+	}
+	if opts.maxSkippableInternalKeys != nil {
+		clone.SetMaxSkippableInternalKeys(*opts.maxSkippableInternalKeys)
+	}
+	if opts.backgroundPurge != nil {
+		clone.SetBackgroundPurgeOnIteratorCleanup(*opts.backgroundPurge)
+	}
+	if opts.ignoreRangeDeletions != nil {
+		clone.SetIgnoreRangeDeletions(*opts.ignoreRangeDeletions)
+	}
+	opts.cloneBounds(clone)
+	return clone
+}
@@ -0,0 +1,26 @@
+//go:build !v6
+// +build !v6
+
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestReadOptionsClone(t *testing.T) {
+	opts := NewDefaultReadOptions()
+	defer opts.Destroy()
+	opts.SetFillCache(false)
+	opts.SetReadTier(BlockCacheTier)
+	opts.SetIterateUpperBound([]byte("z"))
+
+	clone := opts.Clone()
+	defer clone.Destroy()
+	ensure.DeepEqual(t, clone.fillCache, opts.fillCache)
+	ensure.DeepEqual(t, clone.readTier, opts.readTier)
+	ensure.True(t, clone.verifyChecksums == nil)
+	ensure.True(t, clone.cIterateUpperBound != opts.cIterateUpperBound)
+	ensure.DeepEqual(t, clone.iterateUpperBoundSize, opts.iterateUpperBoundSize)
+}
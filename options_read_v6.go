@@ -0,0 +1,29 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+// #include "rocksdb/c.h"
+import "C"
+
+// Clone returns a new ReadOptions with the same settings, the iterate bounds
+// being copied into memory owned by the clone. The snapshot is only copied if
+// it was set with SetSnapshot.
+func (opts *ReadOptions) Clone() *ReadOptions {
+	clone := NewDefaultReadOptions()
+	c := clone.c
+	C.rocksdb_readoptions_set_verify_checksums(c, C.rocksdb_readoptions_get_verify_checksums(opts.c))
+	C.rocksdb_readoptions_set_fill_cache(c, C.rocksdb_readoptions_get_fill_cache(opts.c))
+	C.rocksdb_readoptions_set_read_tier(c, C.rocksdb_readoptions_get_read_tier(opts.c))
+	C.rocksdb_readoptions_set_tailing(c, C.rocksdb_readoptions_get_tailing(opts.c))
+	C.rocksdb_readoptions_set_pin_data(c, C.rocksdb_readoptions_get_pin_data(opts.c))
+	C.rocksdb_readoptions_set_readahead_size(c, C.rocksdb_readoptions_get_readahead_size(opts.c))
+	C.rocksdb_readoptions_set_total_order_seek(c, C.rocksdb_readoptions_get_total_order_seek(opts.c))
+	C.rocksdb_readoptions_set_max_skippable_internal_keys(c, C.rocksdb_readoptions_get_max_skippable_internal_keys(opts.c))
+	C.rocksdb_readoptions_set_background_purge_on_iterator_cleanup(c,
+		C.rocksdb_readoptions_get_background_purge_on_iterator_cleanup(opts.c))
+	C.rocksdb_readoptions_set_ignore_range_deletions(c, C.rocksdb_readoptions_get_ignore_range_deletions(opts.c))
+	C.rocksdb_readoptions_set_prefix_same_as_start(c, C.rocksdb_readoptions_get_prefix_same_as_start(opts.c))
+	opts.cloneBounds(clone)
+	return clone
+}
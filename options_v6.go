@@ -7,7 +7,15 @@ package gorocksdb
 import "C"
 
 func (opts *Options) Clone() *Options {
-	return NewNativeOptions(C.rocksdb_options_create_copy(opts.c))
+	clone := NewNativeOptions(C.rocksdb_options_create_copy(opts.c))
+	clone.compressionPerLevel = opts.compressionPerLevel
+	clone.maxBytesForLevelMultiplierAdditional = opts.maxBytesForLevelMultiplierAdditional
+	clone.dbLogDir = opts.dbLogDir
+	clone.walDir = opts.walDir
+	clone.comparatorName = opts.comparatorName
+	clone.mergeOperatorName = opts.mergeOperatorName
+	clone.mergeFailures = opts.mergeFailures
+	return clone
 }
 
 // SetAtomicFlush sets atomic_flush
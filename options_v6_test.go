@@ -0,0 +1,93 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestOptionsGetters(t *testing.T) {
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+
+	opts.SetCreateIfMissing(true)
+	opts.SetWriteBufferSize(8 << 20)
+	opts.SetMaxOpenFiles(42)
+	opts.SetNumLevels(4)
+	opts.SetCompressionPerLevel([]CompressionType{NoCompression, SnappyCompression, ZSTDCompression, ZSTDCompression})
+	opts.SetMaxBytesForLevelBase(64 << 20)
+	opts.SetMaxBytesForLevelMultiplier(8)
+	opts.SetCompactionStyle(UniversalCompactionStyle)
+	opts.SetUseFsync(true)
+	opts.SetReportBgIoStats(true)
+	opts.SetWalDir("/tmp/wal")
+
+	ensure.True(t, opts.GetCreateIfMissing())
+	ensure.DeepEqual(t, opts.GetWriteBufferSize(), 8<<20)
+	ensure.DeepEqual(t, opts.GetMaxOpenFiles(), 42)
+	ensure.DeepEqual(t, opts.GetNumLevels(), 4)
+	ensure.DeepEqual(t, opts.GetCompressionPerLevel(), []CompressionType{NoCompression, SnappyCompression, ZSTDCompression, ZSTDCompression})
+	ensure.DeepEqual(t, opts.GetMaxBytesForLevelBase(), uint64(64<<20))
+	ensure.DeepEqual(t, opts.GetMaxBytesForLevelMultiplier(), 8.0)
+	ensure.DeepEqual(t, opts.GetCompactionStyle(), UniversalCompactionStyle)
+	ensure.True(t, opts.GetUseFsync())
+	ensure.True(t, opts.GetReportBgIoStats())
+	ensure.False(t, opts.GetInplaceUpdateSupport())
+	ensure.DeepEqual(t, opts.GetWalDir(), "/tmp/wal")
+
+	clone := opts.Clone()
+	defer clone.Destroy()
+	ensure.DeepEqual(t, clone.GetMaxOpenFiles(), 42)
+	ensure.DeepEqual(t, clone.GetWalDir(), "/tmp/wal")
+}
+
+func TestOptionsString(t *testing.T) {
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetMaxOpenFiles(42)
+	opts.SetCompressionPerLevel([]CompressionType{NoCompression, LZ4Compression})
+
+	dump := opts.String()
+	ensure.True(t, strings.HasPrefix(dump, "[DBOptions]\n"), dump)
+	ensure.StringContains(t, dump, "\n  max_open_files=42\n")
+	ensure.StringContains(t, dump, "\n[CFOptions \"default\"]\n")
+	ensure.StringContains(t, dump, "\n  bottommost_compression=kDisableCompressionOption\n")
+	ensure.StringContains(t, dump, "\n  compression_per_level=kNoCompression:kLZ4Compression\n")
+	// the settings the C API can't read back are left out until set
+	ensure.False(t, strings.Contains(dump, "wal_dir="), dump)
+	opts.SetWalDir("")
+	ensure.StringContains(t, opts.String(), "\n  wal_dir=\n")
+	dump = opts.String()
+
+	other := opts.Clone()
+	defer other.Destroy()
+	ensure.DeepEqual(t, other.String(), dump)
+	other.SetMaxOpenFiles(7)
+	ensure.StringContains(t, other.String(), "\n  max_open_files=7\n")
+}
+
+func TestLoadLatestOptions(t *testing.T) {
+	db, _, cleanup := newTestDBCF(t, "TestLoadLatestOptions")
+	path := db.Name()
+	cleanup()
+
+	cache := NewLRUCache(1 << 20)
+	defer cache.Destroy()
+	loaded, err := LoadLatestOptions(path, nil, cache)
+	ensure.Nil(t, err)
+	defer loaded.Destroy()
+	ensure.DeepEqual(t, loaded.ColumnFamilyNames, []string{"default", "guide"})
+	ensure.True(t, loaded.DBOptions.GetCreateIfMissing())
+	ensure.Nil(t, CheckComparatorsAndMergeOperators(path, loaded.ColumnFamilyNames, loaded.ColumnFamilyOptions))
+
+	db, cfh, err := OpenDbColumnFamilies(loaded.DBOptions, path, loaded.ColumnFamilyNames, loaded.ColumnFamilyOptions)
+	ensure.Nil(t, err)
+	for _, cf := range cfh {
+		cf.Destroy()
+	}
+	db.Close()
+}
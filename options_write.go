@@ -16,7 +16,9 @@ func NewDefaultWriteOptions() *WriteOptions {
 
 // NewNativeWriteOptions creates a WriteOptions object.
 func NewNativeWriteOptions(c *C.rocksdb_writeoptions_t) *WriteOptions {
-	return &WriteOptions{c}
+	opts := &WriteOptions{c}
+	trackAlloc("WriteOptions", opts)
+	return opts
 }
 
 // SetSync sets the sync mode. If true, the write will be flushed
@@ -66,6 +68,7 @@ func (opts *WriteOptions) SetLowPri(value bool) {
 
 // Destroy deallocates the WriteOptions object.
 func (opts *WriteOptions) Destroy() {
+	trackFree(opts)
 	C.rocksdb_writeoptions_destroy(opts.c)
 	opts.c = nil
 }
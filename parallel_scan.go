@@ -0,0 +1,329 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// partitionsPerWorker is the number of partitions a ParallelScan aims to give
+// each worker, so that a slow partition doesn't leave the others idle.
+const partitionsPerWorker = 4
+
+// ScanProgress reports the progress of a ParallelScan.
+type ScanProgress struct {
+	// Partitions is the number of partitions the range was split into.
+	Partitions int
+	// PartitionsDone is the number of partitions fully scanned.
+	PartitionsDone int
+	// Keys is the number of keys scanned so far.
+	Keys uint64
+	// Bytes is the size of the keys and values scanned so far.
+	Bytes uint64
+}
+
+// ParallelScanOptions configures a ParallelScan.
+type ParallelScanOptions struct {
+	// Workers is the number of goroutines scanning the range. Default: 1.
+	Workers int
+	// ReadOptions are copied for the iterators of the scan, whose snapshot
+	// and iterate bounds are replaced. Default: the default read options.
+	ReadOptions *ReadOptions
+	// Compare orders the keys like the comparator of the column family,
+	// it is used to split the range. Default: bytes.Compare.
+	Compare func(a, b []byte) int
+	// Progress, if not nil, is called each time a partition is done.
+	// Calls are serialized.
+	Progress func(ScanProgress)
+}
+
+// ParallelScan calls fn for every key in the Range r of the column family,
+// from several workers each iterating over a part of the range.
+// See ParallelScanWithOptions.
+func (db *DB) ParallelScan(ctx context.Context, cf *ColumnFamilyHandle, r Range, workers int,
+	fn func(key, value []byte) error) error {
+	return db.ParallelScanWithOptions(ctx, cf, r, ParallelScanOptions{Workers: workers}, fn)
+}
+
+// ParallelScanWithOptions calls fn for every key in the Range r of the column
+// family, or of the default column family if cf is nil. A nil Range.Start or
+// Range.Limit leaves that side of the range open.
+//
+// The range is split into parts scanned concurrently by independent
+// iterators reading from a shared snapshot, so the scan sees a consistent
+// view of the database. Keys are visited in order within a part, but parts
+// are visited in no particular order, so fn must be safe for concurrent use.
+// The key and value passed to fn are only valid until it returns.
+//
+// The range is split on the boundaries of the live SST files of the column
+// family. When there are none in the range, or the files of the column
+// family can't be told apart, the range is split between its first and last
+// keys, interpolated as byte strings, in parts of similar approximate sizes
+// with RocksDB 6 and of similar key ranges with RocksDB 5. The files can't be
+// told apart with RocksDB 5, whose C API doesn't report the column family of
+// the files, nor before RocksDB 7 for the column families whose handles are
+// not tracked by DB.ColumnFamilies. The interpolated parts are only balanced
+// if Compare orders the keys bytewise.
+//
+// The scan stops at the first error returned by fn or when ctx is done, and
+// returns that error.
+func (db *DB) ParallelScanWithOptions(ctx context.Context, cf *ColumnFamilyHandle, r Range,
+	opts ParallelScanOptions, fn func(key, value []byte) error) error {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.Compare == nil {
+		opts.Compare = bytes.Compare
+	}
+
+	snapshot := db.NewSnapshot()
+	defer db.ReleaseSnapshot(snapshot)
+	var ro *ReadOptions
+	if opts.ReadOptions != nil {
+		ro = opts.ReadOptions.Clone()
+	} else {
+		ro = NewDefaultReadOptions()
+	}
+	defer ro.Destroy()
+	ro.SetSnapshot(snapshot)
+
+	parts := db.splitRange(cf, r, opts.Workers*partitionsPerWorker, opts.Compare)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		progress = ScanProgress{Partitions: len(parts)}
+		work     = make(chan Range)
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+		cancel()
+	}
+
+	for i := 0; i < opts.Workers && i < len(parts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range work {
+				keys, size, err := db.scanPartition(ctx, ro, cf, part, fn)
+
+				mu.Lock()
+				progress.Keys += keys
+				progress.Bytes += size
+				if err == nil {
+					progress.PartitionsDone++
+					if opts.Progress != nil {
+						opts.Progress(progress)
+					}
+				}
+				mu.Unlock()
+
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, part := range parts {
+		select {
+		case work <- part:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if firstErr == nil && progress.PartitionsDone < len(parts) {
+		// the parent context was done before all the parts were dispatched
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// scanPartition calls fn for every key of the part, returning the number of
+// keys and bytes scanned.
+func (db *DB) scanPartition(ctx context.Context, ro *ReadOptions, cf *ColumnFamilyHandle, part Range,
+	fn func(key, value []byte) error) (keys, size uint64, err error) {
+	var iter *Iterator
+	if cf == nil {
+		iter = db.NewIteratorWithBounds(ro, part.Start, part.Limit)
+	} else {
+		iter = db.NewIteratorCFWithBounds(ro, cf, part.Start, part.Limit)
+	}
+	defer iter.Close()
+
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		if keys%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return keys, size, err
+			}
+		}
+		key, value := iter.KeyData(), iter.ValueData()
+		if err := fn(key, value); err != nil {
+			return keys, size, err
+		}
+		keys++
+		size += uint64(len(key) + len(value))
+	}
+	return keys, size, iter.Err()
+}
+
+// splitRange splits r into at most n parts on the smallest keys of the live
+// SST files of the column family, or on the keys returned by
+// interpolatedBounds if there are none in the range.
+func (db *DB) splitRange(cf *ColumnFamilyHandle, r Range, n int, compare func(a, b []byte) int) []Range {
+	bounds := db.fileBounds(cf, r, compare)
+	if len(bounds) == 0 {
+		bounds = db.interpolatedBounds(cf, r, n, compare)
+	}
+	sort.Slice(bounds, func(i, j int) bool { return compare(bounds[i], bounds[j]) < 0 })
+
+	// keep n-1 evenly spaced distinct bounds
+	var splits [][]byte
+	for i := 1; i < n && len(bounds) > 0; i++ {
+		key := bounds[i*len(bounds)/n]
+		if len(splits) == 0 || compare(splits[len(splits)-1], key) < 0 {
+			splits = append(splits, key)
+		}
+	}
+
+	parts := make([]Range, 0, len(splits)+1)
+	start := r.Start
+	for _, split := range splits {
+		parts = append(parts, Range{Start: start, Limit: split})
+		start = split
+	}
+	return append(parts, Range{Start: start, Limit: r.Limit})
+}
+
+// fileBounds returns the smallest keys of the live SST files of the column
+// family within r, none if the name of the column family is unknown.
+func (db *DB) fileBounds(cf *ColumnFamilyHandle, r Range, compare func(a, b []byte) int) [][]byte {
+	name := db.columnFamilyName(cf)
+	var bounds [][]byte
+	for _, file := range db.GetLiveFilesMetaData() {
+		if name == "" || file.ColumnFamilyName != name {
+			continue
+		}
+		key := file.SmallestKey
+		if (r.Start != nil && compare(key, r.Start) <= 0) || (r.Limit != nil && compare(key, r.Limit) >= 0) {
+			continue
+		}
+		bounds = append(bounds, key)
+	}
+	return bounds
+}
+
+// interpolatedBounds returns n-1 keys splitting r in parts of similar
+// approximate sizes, interpolated between the first and last keys of r. The
+// keys are evenly spaced if the approximate sizes are unknown.
+func (db *DB) interpolatedBounds(cf *ColumnFamilyHandle, r Range, n int, compare func(a, b []byte) int) [][]byte {
+	if n < 2 {
+		return nil
+	}
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	ro.SetFillCache(false)
+	var iter *Iterator
+	if cf == nil {
+		iter = db.NewIteratorWithBounds(ro, r.Start, r.Limit)
+	} else {
+		iter = db.NewIteratorCFWithBounds(ro, cf, r.Start, r.Limit)
+	}
+	defer iter.Close()
+	iter.SeekToFirst()
+	if !iter.Valid() {
+		return nil
+	}
+	first := copyBytes(iter.KeyData())
+	iter.SeekToLast()
+	if !iter.Valid() {
+		return nil
+	}
+	last := copyBytes(iter.KeyData())
+
+	// sample more keys than needed to pick the ones balancing the sizes
+	var keys [][]byte
+	for _, key := range interpolateKeys(first, last, n*partitionsPerWorker) {
+		if compare(first, key) < 0 && compare(key, last) < 0 &&
+			(len(keys) == 0 || compare(keys[len(keys)-1], key) < 0) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ranges := make([]Range, len(keys)+1)
+	start := first
+	for i, key := range keys {
+		ranges[i] = Range{Start: start, Limit: key}
+		start = key
+	}
+	ranges[len(keys)] = Range{Start: start, Limit: append(copyBytes(last), 0)}
+	sizes, err := db.approximateSizes(cf, ranges)
+	var total uint64
+	for _, size := range sizes {
+		total += size
+	}
+	if err != nil || total == 0 {
+		return keys
+	}
+
+	// the keys closing the ranges where the cumulated size crosses i/n of
+	// the total
+	var bounds [][]byte
+	var cumulated uint64
+	for i, key := range keys {
+		cumulated += sizes[i]
+		if cumulated*uint64(n) >= total*uint64(len(bounds)+1) {
+			bounds = append(bounds, key)
+			if len(bounds) == n-1 {
+				break
+			}
+		}
+	}
+	return bounds
+}
+
+// interpolateKeys returns the m-1 keys evenly spaced between a and b, taken
+// as big-endian fractions, a being bytewise smaller than b.
+func interpolateKeys(a, b []byte, m int) [][]byte {
+	size := len(a)
+	if len(b) > size {
+		size = len(b)
+	}
+	// an extra byte leaves room between close keys
+	size++
+	pad := func(key []byte) *big.Int {
+		buf := make([]byte, size)
+		copy(buf, key)
+		return new(big.Int).SetBytes(buf)
+	}
+	lo, hi := pad(a), pad(b)
+	step := new(big.Int).Sub(hi, lo)
+
+	keys := make([][]byte, 0, m-1)
+	for i := 1; i < m; i++ {
+		v := new(big.Int).Mul(step, big.NewInt(int64(i)))
+		v.Div(v, big.NewInt(int64(m)))
+		v.Add(v, lo)
+		key := v.FillBytes(make([]byte, size))
+		keys = append(keys, bytes.TrimRight(key, "\x00"))
+	}
+	return keys
+}
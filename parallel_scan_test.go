@@ -0,0 +1,174 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDBParallelScan(t *testing.T) {
+	db := newTestDB(t, "TestDBParallelScan", func(opts *Options) {
+		opts.SetDisableAutoCompactions(true)
+	})
+	defer db.Close()
+
+	// spread the keys over several files
+	wo := NewDefaultWriteOptions()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	for i := 0; i < 1000; i++ {
+		ensure.Nil(t, db.Put(wo, []byte(fmt.Sprintf("key%04d", i)), []byte("val")))
+		if i%100 == 99 {
+			ensure.Nil(t, db.Flush(fo))
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		seen     = make(map[string]bool)
+		progress []ScanProgress
+	)
+	opts := ParallelScanOptions{
+		Workers: 4,
+		Progress: func(p ScanProgress) {
+			progress = append(progress, p)
+		},
+	}
+	r := Range{Start: []byte("key0100"), Limit: []byte("key0900")}
+	err := db.ParallelScanWithOptions(context.Background(), nil, r, opts, func(key, value []byte) error {
+		mu.Lock()
+		seen[string(key)] = true
+		mu.Unlock()
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(seen), 800)
+	ensure.True(t, seen["key0100"])
+	ensure.False(t, seen["key0900"])
+
+	last := progress[len(progress)-1]
+	ensure.True(t, last.Partitions > 1)
+	ensure.DeepEqual(t, last.PartitionsDone, last.Partitions)
+	ensure.DeepEqual(t, last.Keys, uint64(800))
+	ensure.DeepEqual(t, last.Bytes, uint64(800*10))
+
+	// the first error stops the scan
+	givenErr := errors.New("stop")
+	err = db.ParallelScan(context.Background(), nil, Range{}, 4, func(key, value []byte) error {
+		return givenErr
+	})
+	ensure.DeepEqual(t, err, givenErr)
+
+	// so does a done context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = db.ParallelScan(ctx, nil, Range{}, 4, func(key, value []byte) error {
+		return nil
+	})
+	ensure.DeepEqual(t, err, context.Canceled)
+}
+
+func TestDBParallelScanColumnFamily(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorocksdb-TestDBParallelScanColumnFamily")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+	opts.SetDisableAutoCompactions(true)
+	db, err := OpenDbWithColumnFamilies(opts, dir, map[string]*Options{"guide": opts})
+	ensure.Nil(t, err)
+	defer db.Close()
+	cf := db.ColumnFamilies().CF("guide")
+
+	// the default column family holds keys ordered before the ones of guide,
+	// which are ingested in several files
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	fo := NewDefaultFlushOptions()
+	defer fo.Destroy()
+	envOpts := NewDefaultEnvOptions()
+	defer envOpts.Destroy()
+	ingestOpts := NewDefaultIngestExternalFileOptions()
+	defer ingestOpts.Destroy()
+	for i := 0; i < 500; i += 100 {
+		w := NewSSTFileWriter(envOpts, opts)
+		file := filepath.Join(dir, fmt.Sprintf("ingest%d.sst", i))
+		ensure.Nil(t, w.Open(file))
+		for j := i; j < i+100; j++ {
+			ensure.Nil(t, db.Put(wo, []byte(fmt.Sprintf("a%04d", j)), []byte("val")))
+			ensure.Nil(t, w.Add([]byte(fmt.Sprintf("key%04d", j)), []byte("val")))
+		}
+		ensure.Nil(t, w.Finish())
+		w.Destroy()
+		ensure.Nil(t, db.IngestExternalFileCF(cf, []string{file}, ingestOpts))
+		ensure.Nil(t, db.Flush(fo))
+	}
+
+	parts := db.splitRange(cf, Range{}, 4, bytes.Compare)
+	if db.GetLiveFilesMetaData()[0].ColumnFamilyName != "" {
+		// RocksDB 5 doesn't report the column family of the files
+		ensure.True(t, len(parts) > 1)
+	}
+	for _, part := range parts[1:] {
+		ensure.True(t, bytes.HasPrefix(part.Start, []byte("key")))
+	}
+
+	var keys int64
+	err = db.ParallelScan(context.Background(), cf, Range{}, 4, func(key, value []byte) error {
+		ensure.True(t, bytes.HasPrefix(key, []byte("key")))
+		atomic.AddInt64(&keys, 1)
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, keys, int64(500))
+}
+
+func TestDBParallelScanMemtable(t *testing.T) {
+	db := newTestDB(t, "TestDBParallelScanMemtable", nil)
+	defer db.Close()
+
+	// without files, the range is split between its first and last keys
+	wo := NewDefaultWriteOptions()
+	for i := 0; i < 1000; i++ {
+		ensure.Nil(t, db.Put(wo, []byte(fmt.Sprintf("key%04d", i)), []byte("val")))
+	}
+
+	var (
+		keys       int64
+		partitions int
+	)
+	opts := ParallelScanOptions{
+		Workers:  4,
+		Progress: func(p ScanProgress) { partitions = p.Partitions },
+	}
+	err := db.ParallelScanWithOptions(context.Background(), nil, Range{}, opts, func(key, value []byte) error {
+		atomic.AddInt64(&keys, 1)
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, keys, int64(1000))
+	ensure.True(t, partitions > 1)
+}
+
+func TestInterpolateKeys(t *testing.T) {
+	keys := interpolateKeys([]byte("a"), []byte("b"), 4)
+	ensure.DeepEqual(t, keys, [][]byte{[]byte("a@"), []byte("a\x80"), []byte("a\xc0")})
+
+	prev := []byte("key0100")
+	for _, key := range interpolateKeys(prev, []byte("key0900"), 8) {
+		ensure.True(t, bytes.Compare(prev, key) < 0)
+		prev = key
+	}
+	ensure.True(t, bytes.Compare(prev, []byte("key0900")) < 0)
+}
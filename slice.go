@@ -22,7 +22,11 @@ func (slices Slices) Destroy() {
 
 // NewSlice returns a slice with the given data.
 func NewSlice(data *C.char, size C.size_t) *Slice {
-	return &Slice{data, size, false}
+	s := &Slice{data, size, false}
+	if data != nil {
+		trackAlloc("Slice", s)
+	}
+	return s
 }
 
 // StringToSlice is similar to NewSlice, but can be called with
@@ -51,6 +55,7 @@ func (s *Slice) Exists() bool {
 // Free frees the slice data.
 func (s *Slice) Free() {
 	if !s.freed {
+		trackFree(s)
 		C.free(unsafe.Pointer(s.data))
 		s.freed = true
 	}
@@ -63,7 +68,11 @@ type PinnableSliceHandle struct {
 
 // NewNativePinnableSliceHandle creates a PinnableSliceHandle object.
 func NewNativePinnableSliceHandle(c *C.rocksdb_pinnableslice_t) *PinnableSliceHandle {
-	return &PinnableSliceHandle{c}
+	h := &PinnableSliceHandle{c}
+	if c != nil {
+		trackAlloc("PinnableSliceHandle", h)
+	}
+	return h
 }
 
 // Data returns the data of the slice.
@@ -80,5 +89,24 @@ func (h *PinnableSliceHandle) Data() []byte {
 
 // Destroy calls the destructor of the underlying pinnable slice handle.
 func (h *PinnableSliceHandle) Destroy() {
+	trackFree(h)
 	C.rocksdb_pinnableslice_destroy(h.c)
 }
+
+// PinnableSliceHandles is a slice of PinnableSliceHandle.
+type PinnableSliceHandles []*PinnableSliceHandle
+
+// Destroy destroys all the handles.
+func (handles PinnableSliceHandles) Destroy() {
+	for _, h := range handles {
+		h.Destroy()
+	}
+}
+
+func newPinnableSliceHandles(cHandles []*C.rocksdb_pinnableslice_t) PinnableSliceHandles {
+	handles := make(PinnableSliceHandles, len(cHandles))
+	for i, c := range cHandles {
+		handles[i] = NewNativePinnableSliceHandle(c)
+	}
+	return handles
+}
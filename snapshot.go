@@ -2,6 +2,15 @@ package gorocksdb
 
 // #include "rocksdb/c.h"
 import "C"
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
 
 // Snapshot provides a consistent view of read operations in a DB.
 type Snapshot struct {
@@ -10,5 +19,139 @@ type Snapshot struct {
 
 // NewNativeSnapshot creates a Snapshot object.
 func NewNativeSnapshot(c *C.rocksdb_snapshot_t) *Snapshot {
-	return &Snapshot{c}
+	snapshot := &Snapshot{c}
+	trackAlloc("Snapshot", snapshot)
+	return snapshot
+}
+
+// ManagedSnapshot pairs a snapshot with the ReadOptions reading from it,
+// so both are released together.
+type ManagedSnapshot struct {
+	db       *DB
+	snapshot *Snapshot
+	ro       *ReadOptions
+}
+
+// NewManagedSnapshot creates a new snapshot of the database together with
+// a ReadOptions bound to it. Release must be called when done.
+func (db *DB) NewManagedSnapshot() *ManagedSnapshot {
+	snapshot := db.NewSnapshot()
+	ro := NewDefaultReadOptions()
+	ro.SetSnapshot(snapshot)
+	return &ManagedSnapshot{db, snapshot, ro}
+}
+
+// Snapshot returns the underlying snapshot.
+func (s *ManagedSnapshot) Snapshot() *Snapshot {
+	return s.snapshot
+}
+
+// ReadOptions returns the read options bound to the snapshot.
+// They are destroyed on Release and must not be used afterwards.
+func (s *ManagedSnapshot) ReadOptions() *ReadOptions {
+	return s.ro
+}
+
+// Release releases the snapshot and destroys its read options.
+// It is safe to call Release more than once.
+func (s *ManagedSnapshot) Release() {
+	if s.snapshot == nil {
+		return
+	}
+	s.ro.Destroy()
+	s.db.ReleaseSnapshot(s.snapshot)
+	s.ro = nil
+	s.snapshot = nil
+}
+
+// WithSnapshot calls fn with read options bound to a fresh snapshot,
+// releasing the snapshot once fn returns.
+func (db *DB) WithSnapshot(fn func(ro *ReadOptions) error) error {
+	s := db.NewManagedSnapshot()
+	defer s.Release()
+	return fn(s.ro)
+}
+
+// SnapshotInfo describes a snapshot that has not been released yet.
+type SnapshotInfo struct {
+	CreatedAt time.Time
+	// Stack is the goroutine stack that created the snapshot.
+	Stack string
+}
+
+// snapshotTracker records the creation site of every live snapshot.
+type snapshotTracker struct {
+	mu      sync.Mutex
+	enabled bool
+	live    map[*C.rocksdb_snapshot_t]SnapshotInfo
+}
+
+func (t *snapshotTracker) add(c *C.rocksdb_snapshot_t) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.enabled {
+		return
+	}
+	if t.live == nil {
+		t.live = make(map[*C.rocksdb_snapshot_t]SnapshotInfo)
+	}
+	t.live[c] = SnapshotInfo{CreatedAt: time.Now(), Stack: callerStack(3)}
+}
+
+func (t *snapshotTracker) remove(c *C.rocksdb_snapshot_t) {
+	t.mu.Lock()
+	delete(t.live, c)
+	t.mu.Unlock()
+}
+
+// callerStack formats the stack of the calling goroutine, skipping the
+// given number of frames.
+func callerStack(skip int) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// SetSnapshotTracking enables or disables recording the creation stack of
+// every snapshot created by NewSnapshot, for hunting snapshots that are never
+// released. Disabling it forgets all recorded snapshots.
+func (db *DB) SetSnapshotTracking(enabled bool) {
+	db.snapshots.mu.Lock()
+	defer db.snapshots.mu.Unlock()
+	db.snapshots.enabled = enabled
+	if !enabled {
+		db.snapshots.live = nil
+	}
+}
+
+// LiveSnapshots returns the snapshots created while snapshot tracking was
+// enabled and not released yet, oldest first.
+func (db *DB) LiveSnapshots() []SnapshotInfo {
+	db.snapshots.mu.Lock()
+	infos := make([]SnapshotInfo, 0, len(db.snapshots.live))
+	for _, info := range db.snapshots.live {
+		infos = append(infos, info)
+	}
+	db.snapshots.mu.Unlock()
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+	})
+	return infos
+}
+
+// NumSnapshots returns the number of unreleased snapshots in the database,
+// as reported by the "rocksdb.num-snapshots" property. It also counts
+// snapshots created while tracking was disabled, so a value above
+// len(LiveSnapshots()) means some snapshots were not recorded.
+func (db *DB) NumSnapshots() (int, error) {
+	return strconv.Atoi(db.GetProperty("rocksdb.num-snapshots"))
 }
@@ -0,0 +1,61 @@
+package gorocksdb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDBWithSnapshot(t *testing.T) {
+	db := newTestDB(t, "TestDBWithSnapshot", nil)
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.Put(wo, []byte("key"), []byte("v1")))
+
+	err := db.WithSnapshot(func(ro *ReadOptions) error {
+		ensure.Nil(t, db.Put(wo, []byte("key"), []byte("v2")))
+
+		v, err := db.Get(ro, []byte("key"))
+		ensure.Nil(t, err)
+		defer v.Free()
+		ensure.DeepEqual(t, v.Data(), []byte("v1"))
+
+		n, err := db.NumSnapshots()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, n, 1)
+		return nil
+	})
+	ensure.Nil(t, err)
+
+	n, err := db.NumSnapshots()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 0)
+}
+
+func TestDBLiveSnapshots(t *testing.T) {
+	db := newTestDB(t, "TestDBLiveSnapshots", nil)
+	defer db.Close()
+
+	db.SetSnapshotTracking(true)
+
+	s1 := db.NewManagedSnapshot()
+	s2 := db.NewManagedSnapshot()
+
+	live := db.LiveSnapshots()
+	ensure.DeepEqual(t, len(live), 2)
+	ensure.True(t, strings.Contains(live[0].Stack, "TestDBLiveSnapshots"))
+
+	n, err := db.NumSnapshots()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 2)
+
+	s1.Release()
+	s1.Release()
+	ensure.DeepEqual(t, len(db.LiveSnapshots()), 1)
+
+	s2.Release()
+	ensure.DeepEqual(t, len(db.LiveSnapshots()), 0)
+}
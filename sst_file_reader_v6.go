@@ -0,0 +1,80 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+// #include <stdlib.h>
+// #include "rocksdb/c.h"
+// #include "gorocksdb.h"
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// SSTFileReader reads the SST files, such as the ones written by
+// SSTFileWriter, to validate them before they are ingested.
+type SSTFileReader struct {
+	c    *C.gorocksdb_sstfilereader_t
+	path string
+}
+
+// NewSSTFileReader creates an SSTFileReader object. opts must hold the
+// comparator the files were written with.
+func NewSSTFileReader(opts *Options) *SSTFileReader {
+	r := &SSTFileReader{c: C.gorocksdb_sstfilereader_create(opts.c)}
+	trackAlloc("SSTFileReader", r)
+	return r
+}
+
+// Open prepares SSTFileReader to read the file located at "path".
+func (r *SSTFileReader) Open(path string) error {
+	var (
+		cErr  *C.char
+		cPath = C.CString(path)
+	)
+	defer C.free(unsafe.Pointer(cPath))
+	C.gorocksdb_sstfilereader_open(r.c, cPath, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	r.path = path
+	return nil
+}
+
+// NewIterator returns an Iterator over the entries of the file. The
+// iterator must be closed before the reader is destroyed.
+func (r *SSTFileReader) NewIterator(opts *ReadOptions) *Iterator {
+	return newIterator(opts, false, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.gorocksdb_sstfilereader_create_iterator(r.c, opts)
+	})
+}
+
+// VerifyChecksum reads all the blocks of the file, checking their checksums.
+func (r *SSTFileReader) VerifyChecksum() error {
+	var cErr *C.char
+	C.gorocksdb_sstfilereader_verify_checksum(r.c, &cErr)
+	if cErr != nil {
+		defer C.free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+// GetTableProperties returns the properties of the file, read by
+// ReadTableProperties since the reader can't return them through the C API.
+func (r *SSTFileReader) GetTableProperties() (*TableProperties, error) {
+	if r.path == "" {
+		return nil, errors.New("Invalid argument: SSTFileReader is not open")
+	}
+	return ReadTableProperties(r.path)
+}
+
+// Destroy closes the file and deallocates the reader.
+func (r *SSTFileReader) Destroy() {
+	trackFree(r)
+	C.gorocksdb_sstfilereader_destroy(r.c)
+	r.c = nil
+}
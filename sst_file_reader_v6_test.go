@@ -0,0 +1,64 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestSSTFileReader(t *testing.T) {
+	path := newTestSSTFile(t)
+	defer os.Remove(path)
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.Nil(t, r.Open(path))
+
+	props, err := r.GetTableProperties()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, props.NumEntries, uint64(3))
+
+	ro := NewDefaultReadOptions()
+	defer ro.Destroy()
+	iter := r.NewIterator(ro)
+	var keys []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key().Data()))
+	}
+	ensure.Nil(t, iter.Err())
+	iter.Close()
+	ensure.DeepEqual(t, keys, []string{"aaa", "bbb", "ccc"})
+
+	ensure.Nil(t, r.VerifyChecksum())
+}
+
+func TestSSTFileReaderCorruptFile(t *testing.T) {
+	path := newTestSSTFile(t)
+	defer os.Remove(path)
+
+	// the first data block starts the file
+	data, err := ioutil.ReadFile(path)
+	ensure.Nil(t, err)
+	data[0] ^= 0xff
+	ensure.Nil(t, ioutil.WriteFile(path, data, 0644))
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	r := NewSSTFileReader(opts)
+	defer r.Destroy()
+	ensure.Nil(t, r.Open(path))
+	ensure.NotNil(t, r.VerifyChecksum())
+
+	r2 := NewSSTFileReader(opts)
+	defer r2.Destroy()
+	ensure.NotNil(t, r2.Open(path+".missing"))
+	_, err = r2.GetTableProperties()
+	ensure.NotNil(t, err)
+}
@@ -19,7 +19,9 @@ type SSTFileWriter struct {
 // NewSSTFileWriter creates an SSTFileWriter object.
 func NewSSTFileWriter(opts *EnvOptions, dbOpts *Options) *SSTFileWriter {
 	c := C.rocksdb_sstfilewriter_create(opts.c, dbOpts.c)
-	return &SSTFileWriter{c: c}
+	w := &SSTFileWriter{c: c}
+	trackAlloc("SSTFileWriter", w)
+	return w
 }
 
 // Open prepares SstFileWriter to write into file located at "path".
@@ -66,5 +68,6 @@ func (w *SSTFileWriter) Finish() error {
 
 // Destroy destroys the SSTFileWriter object.
 func (w *SSTFileWriter) Destroy() {
+	trackFree(w)
 	C.rocksdb_sstfilewriter_destroy(w.c)
 }
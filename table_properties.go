@@ -0,0 +1,287 @@
+package gorocksdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TableProperties holds the properties RocksDB records in each SST file.
+type TableProperties struct {
+	NumEntries        uint64
+	NumDeletions      uint64
+	NumMergeOperands  uint64
+	NumRangeDeletions uint64
+	NumDataBlocks     uint64
+	DataSize          uint64
+	IndexSize         uint64
+	FilterSize        uint64
+	RawKeySize        uint64
+	RawValueSize      uint64
+	FormatVersion     uint64
+	FixedKeyLength    uint64
+	ColumnFamilyID    uint64
+	// CreationTime is the time the oldest key of the file was written, and
+	// FileCreationTime the time the file was created, in seconds since the
+	// epoch, 0 if unknown.
+	CreationTime        uint64
+	OldestKeyTime       uint64
+	FileCreationTime    uint64
+	ColumnFamilyName    string
+	ComparatorName      string
+	MergeOperatorName   string
+	PrefixExtractorName string
+	FilterPolicyName    string
+	CompressionName     string
+	// UserCollectedProperties holds the properties that don't have a field,
+	// as stored in the file.
+	UserCollectedProperties map[string][]byte
+}
+
+const (
+	blockBasedTableMagicNumber       = 0x88e241b785f4cff7
+	legacyBlockBasedTableMagicNumber = 0xdb4775248b80fb57
+	plainTableMagicNumber            = 0x8242229663bf9564
+	legacyPlainTableMagicNumber      = 0x4f3418eb7a8f13b8
+
+	// footerSize is the size of the footers of the format versions 1 and
+	// later: checksum type, 40 bytes holding the metaindex and index handles
+	// up to the version 5 or the metaindex size from the version 6, format
+	// version and magic number.
+	footerSize       = 1 + 40 + 4 + 8
+	legacyFooterSize = 40 + 8
+	// maxFormatVersion is the last format version whose footer is known.
+	maxFormatVersion = 6
+	// blockTrailerSize is the size of the compression type and checksum
+	// following each block.
+	blockTrailerSize = 5
+	// maxMetaBlockSize bounds the size of the metaindex and properties
+	// blocks read, so that a corrupt handle can't make it allocate the size
+	// of the file.
+	maxMetaBlockSize = 64 << 20
+
+	propertiesBlockName       = "rocksdb.properties"
+	legacyPropertiesBlockName = "rocksdb.stats"
+)
+
+// extendedMagic starts the footers of the format version 6 and later.
+var extendedMagic = []byte{0x3e, 0x00, 0x7a, 0x00}
+
+// ErrCorruptTable is returned for the SST files whose structure can't be read.
+var ErrCorruptTable = errors.New("Corruption: bad table")
+
+// ErrTableNotSupported is returned for the SST files in a format that can't be
+// read, the PlainTable one or a format version newer than the last known.
+var ErrTableNotSupported = errors.New("Not implemented: unsupported table format")
+
+// ReadTableProperties reads the properties of the block based SST file at
+// path, such as the files written by SSTFileWriter. The C API has no SST file
+// reader, so the file is parsed in Go; only the block based table format is
+// supported, not the PlainTable one, up to the format version 6. The files in
+// another format return an error wrapping ErrTableNotSupported.
+func ReadTableProperties(path string) (*TableProperties, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := stat.Size()
+	if size < legacyFooterSize {
+		return nil, fmt.Errorf("%w: file too short to be an SST file: %s", ErrCorruptTable, path)
+	}
+
+	footer := make([]byte, footerSize)
+	if size < footerSize {
+		footer = footer[:legacyFooterSize]
+	}
+	if _, err := f.ReadAt(footer, size-int64(len(footer))); err != nil {
+		return nil, err
+	}
+
+	var metaIndexOffset, metaIndexSize uint64
+	switch magic := binary.LittleEndian.Uint64(footer[len(footer)-8:]); magic {
+	case blockBasedTableMagicNumber:
+		if len(footer) < footerSize {
+			return nil, fmt.Errorf("%w: truncated footer: %s", ErrCorruptTable, path)
+		}
+		version := binary.LittleEndian.Uint32(footer[footerSize-12:])
+		if version > maxFormatVersion {
+			return nil, fmt.Errorf("%w: format version %d: %s", ErrTableNotSupported, version, path)
+		}
+		if version < 6 {
+			var ok bool
+			if metaIndexOffset, metaIndexSize, ok = decodeBlockHandle(footer[1 : footerSize-12]); !ok {
+				return nil, fmt.Errorf("%w: bad metaindex handle: %s", ErrCorruptTable, path)
+			}
+			break
+		}
+		// from the version 6 the metaindex block immediately precedes the
+		// footer, which only records its size
+		if !bytes.Equal(footer[1:5], extendedMagic) {
+			return nil, fmt.Errorf("%w: bad extended magic: %s", ErrCorruptTable, path)
+		}
+		metaIndexSize = uint64(binary.LittleEndian.Uint32(footer[13:17]))
+		if metaIndexSize+blockTrailerSize > uint64(size-footerSize) {
+			return nil, fmt.Errorf("%w: bad metaindex size: %s", ErrCorruptTable, path)
+		}
+		metaIndexOffset = uint64(size-footerSize) - metaIndexSize - blockTrailerSize
+	case legacyBlockBasedTableMagicNumber:
+		var ok bool
+		metaIndexOffset, metaIndexSize, ok = decodeBlockHandle(footer[len(footer)-legacyFooterSize : len(footer)-8])
+		if !ok {
+			return nil, fmt.Errorf("%w: bad metaindex handle: %s", ErrCorruptTable, path)
+		}
+	case plainTableMagicNumber, legacyPlainTableMagicNumber:
+		return nil, fmt.Errorf("%w: PlainTable: %s", ErrTableNotSupported, path)
+	default:
+		return nil, fmt.Errorf("%w: bad magic number %#x: %s", ErrCorruptTable, magic, path)
+	}
+
+	metaIndex, err := readUncompressedBlock(f, size, metaIndexOffset, metaIndexSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metaindex block: %s", err, path)
+	}
+	var propsHandle []byte
+	err = forEachBlockEntry(metaIndex, func(key, value []byte) {
+		if string(key) == propertiesBlockName || (propsHandle == nil && string(key) == legacyPropertiesBlockName) {
+			propsHandle = value
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: metaindex block: %s", err, path)
+	}
+	if propsHandle == nil {
+		return nil, fmt.Errorf("NotFound: no properties block: %s", path)
+	}
+
+	propsOffset, propsSize, ok := decodeBlockHandle(propsHandle)
+	if !ok {
+		return nil, fmt.Errorf("%w: bad properties handle: %s", ErrCorruptTable, path)
+	}
+	block, err := readUncompressedBlock(f, size, propsOffset, propsSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: properties block: %s", err, path)
+	}
+	props := &TableProperties{}
+	if err := forEachBlockEntry(block, props.set); err != nil {
+		return nil, fmt.Errorf("%w: properties block: %s", err, path)
+	}
+	return props, nil
+}
+
+// set sets the property of the given name from its stored value.
+func (props *TableProperties) set(name, value []byte) {
+	uints := map[string]*uint64{
+		"rocksdb.num.entries":         &props.NumEntries,
+		"rocksdb.deleted.keys":        &props.NumDeletions,
+		"rocksdb.merge.operands":      &props.NumMergeOperands,
+		"rocksdb.num.range-deletions": &props.NumRangeDeletions,
+		"rocksdb.num.data.blocks":     &props.NumDataBlocks,
+		"rocksdb.data.size":           &props.DataSize,
+		"rocksdb.index.size":          &props.IndexSize,
+		"rocksdb.filter.size":         &props.FilterSize,
+		"rocksdb.raw.key.size":        &props.RawKeySize,
+		"rocksdb.raw.value.size":      &props.RawValueSize,
+		"rocksdb.format.version":      &props.FormatVersion,
+		"rocksdb.fixed.key.length":    &props.FixedKeyLength,
+		"rocksdb.column.family.id":    &props.ColumnFamilyID,
+		"rocksdb.creation.time":       &props.CreationTime,
+		"rocksdb.oldest.key.time":     &props.OldestKeyTime,
+		"rocksdb.file.creation.time":  &props.FileCreationTime,
+	}
+	strs := map[string]*string{
+		"rocksdb.column.family.name":    &props.ColumnFamilyName,
+		"rocksdb.comparator":            &props.ComparatorName,
+		"rocksdb.merge.operator":        &props.MergeOperatorName,
+		"rocksdb.prefix.extractor.name": &props.PrefixExtractorName,
+		"rocksdb.filter.policy":         &props.FilterPolicyName,
+		"rocksdb.compression":           &props.CompressionName,
+	}
+
+	if field, ok := uints[string(name)]; ok {
+		if v, n := binary.Uvarint(value); n > 0 {
+			*field = v
+			return
+		}
+	}
+	if field, ok := strs[string(name)]; ok {
+		*field = string(value)
+		return
+	}
+	if props.UserCollectedProperties == nil {
+		props.UserCollectedProperties = make(map[string][]byte)
+	}
+	props.UserCollectedProperties[string(name)] = copyBytes(value)
+}
+
+// decodeBlockHandle decodes the offset and size of a block.
+func decodeBlockHandle(b []byte) (offset, size uint64, ok bool) {
+	offset, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, 0, false
+	}
+	size, m := binary.Uvarint(b[n:])
+	return offset, size, m > 0
+}
+
+// readUncompressedBlock reads a block stored without compression, as are the
+// metaindex and properties blocks, from a file of fileSize bytes.
+func readUncompressedBlock(f *os.File, fileSize int64, offset, size uint64) ([]byte, error) {
+	// the block and its trailer must fit in the file
+	if size > maxMetaBlockSize || offset > uint64(fileSize) || size+blockTrailerSize > uint64(fileSize)-offset {
+		return nil, ErrCorruptTable
+	}
+	block := make([]byte, size+blockTrailerSize)
+	if _, err := f.ReadAt(block, int64(offset)); err != nil {
+		return nil, ErrCorruptTable
+	}
+	if compression := block[size]; compression != 0 {
+		return nil, fmt.Errorf("%w: unexpected block compression %d", ErrCorruptTable, compression)
+	}
+	return block[:size], nil
+}
+
+// forEachBlockEntry calls fn with the key and value of each entry of a block
+// in the format of the block based tables: prefix compressed entries followed
+// by the restart points and their number.
+func forEachBlockEntry(block []byte, fn func(key, value []byte)) error {
+	if len(block) < 4 {
+		return ErrCorruptTable
+	}
+	// the high bit of the number of restarts tells the data block index type
+	numRestarts := binary.LittleEndian.Uint32(block[len(block)-4:]) & 0x7fffffff
+	if uint64(numRestarts) > uint64(len(block)-4)/4 {
+		return ErrCorruptTable
+	}
+	end := len(block) - 4 - 4*int(numRestarts)
+
+	var key []byte
+	for pos := 0; pos < end; {
+		var header [3]uint64
+		for i := range header {
+			v, n := binary.Uvarint(block[pos:end])
+			if n <= 0 {
+				return ErrCorruptTable
+			}
+			header[i] = v
+			pos += n
+		}
+		shared, nonShared, valueLen := header[0], header[1], header[2]
+		left := uint64(end - pos)
+		if shared > uint64(len(key)) || nonShared > left || valueLen > left-nonShared {
+			return ErrCorruptTable
+		}
+		key = append(key[:shared], block[pos:pos+int(nonShared)]...)
+		pos += int(nonShared)
+		fn(copyBytes(key), block[pos:pos+int(valueLen)])
+		pos += int(valueLen)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package gorocksdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func newTestSSTFile(t *testing.T) string {
+	envOpts := NewDefaultEnvOptions()
+	defer envOpts.Destroy()
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	w := NewSSTFileWriter(envOpts, opts)
+	defer w.Destroy()
+
+	file, err := ioutil.TempFile("", "gorocksdb-table-properties-test")
+	ensure.Nil(t, err)
+	file.Close()
+
+	ensure.Nil(t, w.Open(file.Name()))
+	ensure.Nil(t, w.Add([]byte("aaa"), []byte("aaaValue")))
+	ensure.Nil(t, w.Add([]byte("bbb"), []byte("bbbValue")))
+	ensure.Nil(t, w.Add([]byte("ccc"), []byte("cccValue")))
+	ensure.Nil(t, w.Finish())
+	return file.Name()
+}
+
+func TestReadTableProperties(t *testing.T) {
+	path := newTestSSTFile(t)
+	defer os.Remove(path)
+
+	props, err := ReadTableProperties(path)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, props.NumEntries, uint64(3))
+	ensure.DeepEqual(t, props.NumDeletions, uint64(0))
+	ensure.DeepEqual(t, props.RawKeySize, uint64(3*(3+8)))
+	ensure.DeepEqual(t, props.RawValueSize, uint64(3*8))
+	ensure.True(t, props.DataSize > 0)
+	ensure.True(t, props.IndexSize > 0)
+	ensure.DeepEqual(t, props.ComparatorName, "leveldb.BytewiseComparator")
+}
+
+func TestReadTablePropertiesCorruptFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "gorocksdb-table-properties-test")
+	ensure.Nil(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.Write(make([]byte, 4096))
+	ensure.Nil(t, err)
+	file.Close()
+
+	_, err = ReadTableProperties(file.Name())
+	ensure.True(t, errors.Is(err, ErrCorruptTable), err)
+}
+
+func TestReadTablePropertiesCorruptHandle(t *testing.T) {
+	path := newTestSSTFile(t)
+	defer os.Remove(path)
+
+	data, err := ioutil.ReadFile(path)
+	ensure.Nil(t, err)
+	// point the metaindex handle of the footer past the end of the file
+	handle := data[len(data)-footerSize+1:]
+	n := binary.PutUvarint(handle, 0)
+	binary.PutUvarint(handle[n:], 1<<62)
+	ensure.Nil(t, ioutil.WriteFile(path, data, 0644))
+
+	_, err = ReadTableProperties(path)
+	ensure.True(t, errors.Is(err, ErrCorruptTable), err)
+}
+
+func TestForEachBlockEntryOverflow(t *testing.T) {
+	// an entry whose value length wraps the remaining size once added to its
+	// key length, followed by no restart points
+	var block []byte
+	for _, v := range []uint64{0, 1, 1<<64 - 1} {
+		buf := make([]byte, binary.MaxVarintLen64)
+		block = append(block, buf[:binary.PutUvarint(buf, v)]...)
+	}
+	block = append(block, 'k', 0, 0, 0, 0)
+
+	err := forEachBlockEntry(block, func(key, value []byte) {
+		t.Fatalf("unexpected entry %q", key)
+	})
+	ensure.DeepEqual(t, err, ErrCorruptTable)
+}
+
+func TestReadTablePropertiesFormatVersion6(t *testing.T) {
+	path := newTestSSTFile(t)
+	defer os.Remove(path)
+	data, err := ioutil.ReadFile(path)
+	ensure.Nil(t, err)
+
+	// rewrite the footer in the format version 6, which only records the size
+	// of the metaindex block moved right before it
+	footer := data[len(data)-footerSize:]
+	if binary.LittleEndian.Uint32(footer[footerSize-12:]) >= 6 {
+		t.Skip("SSTFileWriter already writes the format version 6")
+	}
+	metaIndexOffset, metaIndexSize, ok := decodeBlockHandle(footer[1:])
+	ensure.True(t, ok)
+	metaIndex := copyBytes(data[metaIndexOffset : metaIndexOffset+metaIndexSize+blockTrailerSize])
+	v6Footer := make([]byte, footerSize)
+	v6Footer[0] = footer[0]
+	copy(v6Footer[1:], extendedMagic)
+	binary.LittleEndian.PutUint32(v6Footer[13:], uint32(metaIndexSize))
+	binary.LittleEndian.PutUint32(v6Footer[footerSize-12:], 6)
+	copy(v6Footer[footerSize-8:], footer[footerSize-8:])
+	data = append(append(data[:len(data)-footerSize], metaIndex...), v6Footer...)
+	ensure.Nil(t, ioutil.WriteFile(path, data, 0644))
+
+	props, err := ReadTableProperties(path)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, props.NumEntries, uint64(3))
+
+	// a newer format version is reported as not supported
+	binary.LittleEndian.PutUint32(data[len(data)-12:], maxFormatVersion+1)
+	ensure.Nil(t, ioutil.WriteFile(path, data, 0644))
+	_, err = ReadTableProperties(path)
+	ensure.True(t, errors.Is(err, ErrTableNotSupported), err)
+}
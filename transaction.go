@@ -17,7 +17,9 @@ type Transaction struct {
 
 // NewNativeTransaction creates a Transaction object.
 func NewNativeTransaction(c *C.rocksdb_transaction_t) *Transaction {
-	return &Transaction{c}
+	transaction := &Transaction{c}
+	trackAlloc("Transaction", transaction)
+	return transaction
 }
 
 // Commit commits the transaction to the database.
@@ -189,19 +191,22 @@ func (transaction *Transaction) Merge(key []byte, value []byte) error {
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given.
 func (transaction *Transaction) NewIterator(opts *ReadOptions) *Iterator {
-	return NewNativeIterator(
-		unsafe.Pointer(C.rocksdb_transaction_create_iterator(transaction.c, opts.c)))
+	return newIterator(opts, false, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.rocksdb_transaction_create_iterator(transaction.c, opts)
+	})
 }
 
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given and column family.
 func (transaction *Transaction) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
-	return NewNativeIterator(
-		unsafe.Pointer(C.rocksdb_transaction_create_iterator_cf(transaction.c, opts.c, cf.c)))
+	return newIterator(opts, false, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.rocksdb_transaction_create_iterator_cf(transaction.c, opts, cf.c)
+	})
 }
 
 // Destroy deallocates the transaction object.
 func (transaction *Transaction) Destroy() {
+	trackFree(transaction)
 	C.rocksdb_transaction_destroy(transaction.c)
 	transaction.c = nil
 }
@@ -15,6 +15,7 @@ type TransactionDB struct {
 	name              string
 	opts              *Options
 	transactionDBOpts *TransactionDBOptions
+	cfs               ColumnFamilies
 }
 
 // OpenTransactionDb opens a database with the specified options.
@@ -68,6 +69,7 @@ func (db *TransactionDB) NewSnapshot() *Snapshot {
 
 // ReleaseSnapshot releases the snapshot and its resources.
 func (db *TransactionDB) ReleaseSnapshot(snapshot *Snapshot) {
+	trackFree(snapshot)
 	C.rocksdb_transactiondb_release_snapshot(db.c, snapshot.c)
 	snapshot.c = nil
 }
@@ -80,6 +82,8 @@ func (db *TransactionDB) TransactionBegin(
 	oldTransaction *Transaction,
 ) *Transaction {
 	if oldTransaction != nil {
+		// the old transaction is reused in place and gets a new wrapper
+		trackFree(oldTransaction)
 		return NewNativeTransaction(C.rocksdb_transaction_begin(
 			db.c,
 			opts.c,
@@ -216,8 +220,9 @@ func (db *TransactionDB) Merge(opts *WriteOptions, key []byte, value []byte) err
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given.
 func (db *TransactionDB) NewIterator(opts *ReadOptions) *Iterator {
-	return NewNativeIterator(
-		unsafe.Pointer(C.rocksdb_transactiondb_create_iterator(db.c, opts.c)))
+	return newIterator(opts, false, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.rocksdb_transactiondb_create_iterator(db.c, opts)
+	})
 }
 
 // NewCheckpoint creates a new Checkpoint for this db.
@@ -238,6 +243,7 @@ func (db *TransactionDB) NewCheckpoint() (*Checkpoint, error) {
 
 // Close closes the database.
 func (transactionDB *TransactionDB) Close() {
+	transactionDB.cfs.destroy()
 	C.rocksdb_transactiondb_close(transactionDB.c)
 	transactionDB.c = nil
 }
@@ -278,6 +284,7 @@ func (db *OptimisticTransactionDB) GetBaseDB() *DB {
 		closer: func(c *C.rocksdb_t) { C.rocksdb_optimistictransactiondb_close_base_db(c) },
 		name:   db.name,
 		opts:   db.opts,
+		merges: newMergeFailureLogs(db.opts),
 	}
 }
 
@@ -289,6 +296,8 @@ func (db *OptimisticTransactionDB) TransactionBegin(
 	oldTransaction *Transaction,
 ) *Transaction {
 	if oldTransaction != nil {
+		// the old transaction is reused in place and gets a new wrapper
+		trackFree(oldTransaction)
 		return NewNativeTransaction(C.rocksdb_optimistictransaction_begin(
 			db.c,
 			opts.c,
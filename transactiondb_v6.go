@@ -78,7 +78,13 @@ func OpenTransactionDbColumnFamilies(
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		err = errors.New(C.GoString(cErr))
 	} else {
-		db = &TransactionDB{c, name, opts, transactionDBOpts}
+		db = &TransactionDB{
+			c:                 c,
+			name:              name,
+			opts:              opts,
+			transactionDBOpts: transactionDBOpts,
+		}
+		cfHandles = make([]*ColumnFamilyHandle, numColumnFamilies)
 		for i, c := range cHandles {
 			cfHandles[i] = NewNativeColumnFamilyHandle(c)
 		}
@@ -86,6 +92,38 @@ func OpenTransactionDbColumnFamilies(
 	return
 }
 
+// OpenTransactionDbWithColumnFamilies opens a transactional database with all
+// its column families, and creates the column families of the descriptors it
+// lacks, like OpenDbWithColumnFamilies. The handles are tracked by
+// TransactionDB.ColumnFamilies.
+func OpenTransactionDbWithColumnFamilies(
+	opts *Options,
+	transactionDBOpts *TransactionDBOptions,
+	name string,
+	descriptors map[string]*Options,
+) (*TransactionDB, error) {
+	names, cfOpts, missing, err := columnFamilyDescriptors(opts, name, descriptors)
+	if err != nil {
+		return nil, err
+	}
+	db, handles, err := OpenTransactionDbColumnFamilies(opts, transactionDBOpts, name, names, cfOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	cfs := db.ColumnFamilies()
+	for i, name := range names {
+		cfs.add(name, handles[i])
+	}
+	for _, name := range missing {
+		if _, err := cfs.Create(descriptors[name], name); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
 // Merge merges the data associated with the key with the actual data in the database.
 func (db *TransactionDB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key []byte, value []byte) error {
 	var (
@@ -106,6 +144,7 @@ func (db *TransactionDB) MergeCF(opts *WriteOptions, cf *ColumnFamilyHandle, key
 // NewIterator returns an Iterator over the database that uses the
 // ReadOptions given and column family.
 func (db *TransactionDB) NewIteratorCF(opts *ReadOptions, cf *ColumnFamilyHandle) *Iterator {
-	return NewNativeIterator(
-		unsafe.Pointer(C.rocksdb_transactiondb_create_iterator_cf(db.c, opts.c, cf.c)))
+	return newIterator(opts, false, func(opts *C.rocksdb_readoptions_t) *C.rocksdb_iterator_t {
+		return C.rocksdb_transactiondb_create_iterator_cf(db.c, opts, cf.c)
+	})
 }
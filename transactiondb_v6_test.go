@@ -0,0 +1,38 @@
+//go:build v6
+// +build v6
+
+package gorocksdb
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestTransactionDBColumnFamilies(t *testing.T) {
+	dir, cleanup := newTestDBDir(t, "TestTransactionDBColumnFamilies")
+	defer cleanup()
+
+	opts := NewDefaultOptions()
+	defer opts.Destroy()
+	opts.SetCreateIfMissing(true)
+	txOpts := NewDefaultTransactionDBOptions()
+	defer txOpts.Destroy()
+
+	db, err := OpenTransactionDbWithColumnFamilies(opts, txOpts, dir, map[string]*Options{"guide": opts})
+	ensure.Nil(t, err)
+	defer db.Close()
+
+	cfs := db.ColumnFamilies()
+	ensure.DeepEqual(t, cfs.Names(), []string{"default", "guide"})
+
+	wo := NewDefaultWriteOptions()
+	defer wo.Destroy()
+	ensure.Nil(t, db.PutCF(wo, cfs.CF("guide"), []byte("hello"), []byte("world")))
+
+	_, err = cfs.Create(opts, "extra")
+	ensure.Nil(t, err)
+	// the C API can't drop the column families of a transactional database
+	ensure.NotNil(t, cfs.Drop("extra"))
+	ensure.True(t, cfs.CF("extra") != nil)
+}
@@ -0,0 +1,247 @@
+package gorocksdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ttlMagic starts the expiry header of TTL values, followed by the version of
+// the header and the expiry time.
+const ttlMagic = "\xfettl"
+
+const (
+	ttlVersion    = 1
+	ttlHeaderSize = len(ttlMagic) + 1 + 8
+)
+
+// ErrInvalidTTLValue is returned when reading a value without expiry header
+// through the TTL functions.
+var ErrInvalidTTLValue = errors.New("Corruption: value has no TTL header")
+
+// EncodeTTLValue prefixes the value with its expiry time, as stored by
+// PutWithTTL. The header holds the magic bytes "\xfettl", a version byte, and
+// the expiry time in nanoseconds since the Unix epoch as a big-endian integer,
+// 0 meaning that the value never expires.
+func EncodeTTLValue(value []byte, expiry time.Time) []byte {
+	buf := make([]byte, ttlHeaderSize+len(value))
+	copy(buf, ttlMagic)
+	buf[len(ttlMagic)] = ttlVersion
+	if !expiry.IsZero() {
+		binary.BigEndian.PutUint64(buf[len(ttlMagic)+1:], uint64(expiry.UnixNano()))
+	}
+	copy(buf[ttlHeaderSize:], value)
+	return buf
+}
+
+// DecodeTTLValue splits a value encoded by EncodeTTLValue into the value and
+// its expiry time, zero if it never expires. The value points into data.
+// It returns false if data doesn't start with an expiry header. A value
+// written without TTL is only mistaken for one if it starts with the magic
+// bytes and version of the header.
+func DecodeTTLValue(data []byte) (value []byte, expiry time.Time, ok bool) {
+	if len(data) < ttlHeaderSize || string(data[:len(ttlMagic)]) != ttlMagic || data[len(ttlMagic)] != ttlVersion {
+		return nil, time.Time{}, false
+	}
+	if ns := int64(binary.BigEndian.Uint64(data[len(ttlMagic)+1:])); ns != 0 {
+		expiry = time.Unix(0, ns)
+	}
+	return data[ttlHeaderSize:], expiry, true
+}
+
+// ttlExpired reports whether a value expiring at expiry is expired at now.
+func ttlExpired(expiry, now time.Time) bool {
+	return !expiry.IsZero() && !now.Before(expiry)
+}
+
+// ttlExpiry returns the expiry time of a value written now with the TTL.
+func ttlExpiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// PutWithTTL writes a value expiring after ttl, or never if ttl <= 0.
+//
+// Unlike OpenDbWithTTL, each key has its own TTL, and column families can mix
+// keys with and without TTL. The value must be read with GetWithTTL or a
+// TTLIterator, which hide it once expired, and is removed by compactions if
+// the column family uses a TTLCompactionFilter.
+func (db *DB) PutWithTTL(opts *WriteOptions, key, value []byte, ttl time.Duration) error {
+	return db.Put(opts, key, EncodeTTLValue(value, ttlExpiry(ttl)))
+}
+
+// PutCFWithTTL writes a value expiring after ttl in the column family.
+// See PutWithTTL.
+func (db *DB) PutCFWithTTL(opts *WriteOptions, cf *ColumnFamilyHandle, key, value []byte, ttl time.Duration) error {
+	return db.PutCF(opts, cf, key, EncodeTTLValue(value, ttlExpiry(ttl)))
+}
+
+// GetWithTTL returns a copy of a value written by PutWithTTL and its expiry
+// time, zero if it never expires. An expired value is not found, even if it
+// was not removed by a compaction yet.
+func (db *DB) GetWithTTL(opts *ReadOptions, key []byte) (value []byte, expiry time.Time, err error) {
+	data, err := db.GetBytes(opts, key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return decodeTTLGet(data)
+}
+
+// GetCFWithTTL returns a copy of a value written by PutCFWithTTL in the column
+// family and its expiry time. See GetWithTTL.
+func (db *DB) GetCFWithTTL(opts *ReadOptions, cf *ColumnFamilyHandle, key []byte) (value []byte, expiry time.Time, err error) {
+	data, err := db.GetBytesCF(opts, cf, key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return decodeTTLGet(data)
+}
+
+func decodeTTLGet(data []byte) ([]byte, time.Time, error) {
+	if data == nil {
+		return nil, time.Time{}, nil
+	}
+	value, expiry, ok := DecodeTTLValue(data)
+	if !ok {
+		return nil, time.Time{}, ErrInvalidTTLValue
+	}
+	if ttlExpired(expiry, time.Now()) {
+		return nil, time.Time{}, nil
+	}
+	return value, expiry, nil
+}
+
+// TTLCompactionFilter is a CompactionFilter removing the values written by
+// PutWithTTL once expired. Values without expiry header are kept.
+type TTLCompactionFilter struct{}
+
+// Filter implements CompactionFilter.
+func (TTLCompactionFilter) Filter(level int, key, val []byte) (remove bool, newVal []byte) {
+	_, expiry, ok := DecodeTTLValue(val)
+	return ok && ttlExpired(expiry, time.Now()), nil
+}
+
+// Name implements CompactionFilter.
+func (TTLCompactionFilter) Name() string { return "gorocksdb.ttl" }
+
+// TTLIterator iterates over values written by PutWithTTL, skipping the
+// expired ones and hiding the expiry headers. Values are considered at the
+// time the TTLIterator was created.
+type TTLIterator struct {
+	iter   *Iterator
+	now    time.Time
+	value  []byte
+	expiry time.Time
+	err    error
+}
+
+// NewTTLIterator creates a TTLIterator over the iterator, which it takes
+// ownership of.
+func NewTTLIterator(iter *Iterator) *TTLIterator {
+	return &TTLIterator{iter: iter, now: time.Now()}
+}
+
+// Valid returns false only when an Iterator has iterated past either the
+// first or the last key in the database, or met a value without expiry
+// header.
+func (t *TTLIterator) Valid() bool {
+	return t.err == nil && t.iter.Valid()
+}
+
+// SeekToFirst moves the iterator to the first unexpired key.
+func (t *TTLIterator) SeekToFirst() {
+	t.iter.SeekToFirst()
+	t.skipExpired(false)
+}
+
+// SeekToLast moves the iterator to the last unexpired key.
+func (t *TTLIterator) SeekToLast() {
+	t.iter.SeekToLast()
+	t.skipExpired(true)
+}
+
+// Seek moves the iterator to the first unexpired key greater than or equal
+// to the key.
+func (t *TTLIterator) Seek(key []byte) {
+	t.iter.Seek(key)
+	t.skipExpired(false)
+}
+
+// SeekForPrev moves the iterator to the last unexpired key less than or
+// equal to the key.
+func (t *TTLIterator) SeekForPrev(key []byte) {
+	t.iter.SeekForPrev(key)
+	t.skipExpired(true)
+}
+
+// Next moves the iterator to the next unexpired key.
+func (t *TTLIterator) Next() {
+	t.iter.Next()
+	t.skipExpired(false)
+}
+
+// Prev moves the iterator to the previous unexpired key.
+func (t *TTLIterator) Prev() {
+	t.iter.Prev()
+	t.skipExpired(true)
+}
+
+// Key returns the key the iterator currently holds, valid until the iterator
+// moves.
+func (t *TTLIterator) Key() []byte {
+	return t.iter.KeyData()
+}
+
+// Value returns the value the iterator currently holds without its expiry
+// header, valid until the iterator moves.
+func (t *TTLIterator) Value() []byte {
+	return t.value
+}
+
+// Expiry returns the expiry time of the current value, zero if it never
+// expires.
+func (t *TTLIterator) Expiry() time.Time {
+	return t.expiry
+}
+
+// Err returns nil if no errors happened during iteration, or the actual
+// error otherwise.
+func (t *TTLIterator) Err() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.iter.Err()
+}
+
+// Close closes the iterator.
+func (t *TTLIterator) Close() {
+	t.iter.Close()
+}
+
+// skipExpired moves the underlying iterator in the given direction until it
+// holds an unexpired value.
+func (t *TTLIterator) skipExpired(reverse bool) {
+	t.err = nil
+	for ; t.iter.Valid(); t.step(reverse) {
+		value, expiry, ok := DecodeTTLValue(t.iter.ValueData())
+		if !ok {
+			t.err = ErrInvalidTTLValue
+			return
+		}
+		if !ttlExpired(expiry, t.now) {
+			t.value, t.expiry = value, expiry
+			return
+		}
+	}
+	t.value, t.expiry = nil, time.Time{}
+}
+
+func (t *TTLIterator) step(reverse bool) {
+	if reverse {
+		t.iter.Prev()
+	} else {
+		t.iter.Next()
+	}
+}
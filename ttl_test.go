@@ -0,0 +1,72 @@
+package gorocksdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+)
+
+func TestDBTTL(t *testing.T) {
+	db := newTestDB(t, "TestDBTTL", func(opts *Options) {
+		opts.SetCompactionFilter(TTLCompactionFilter{})
+	})
+	defer db.Close()
+
+	wo := NewDefaultWriteOptions()
+	ro := NewDefaultReadOptions()
+	ensure.Nil(t, db.PutWithTTL(wo, []byte("key1"), []byte("forever"), 0))
+	ensure.Nil(t, db.PutWithTTL(wo, []byte("key2"), []byte("hour"), time.Hour))
+	ensure.Nil(t, db.Put(wo, []byte("key3"), EncodeTTLValue([]byte("expired"), time.Now().Add(-time.Second))))
+	ensure.Nil(t, db.Put(wo, []byte("raw"), []byte("raw")))
+	// a plain value long enough to hold a header, whose first 8 bytes would
+	// decode to an expiry time in the past
+	ensure.Nil(t, db.Put(wo, []byte("raw8"), []byte("\x00\x00\x00\x00\x00\x00\x00\x01plain")))
+
+	v, expiry, err := db.GetWithTTL(ro, []byte("key1"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("forever"))
+	ensure.True(t, expiry.IsZero())
+
+	v, expiry, err = db.GetWithTTL(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, []byte("hour"))
+	ensure.True(t, expiry.After(time.Now().Add(59*time.Minute)))
+
+	v, _, err = db.GetWithTTL(ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, v == nil)
+
+	_, _, err = db.GetWithTTL(ro, []byte("raw"))
+	ensure.DeepEqual(t, err, ErrInvalidTTLValue)
+	_, _, err = db.GetWithTTL(ro, []byte("raw8"))
+	ensure.DeepEqual(t, err, ErrInvalidTTLValue)
+	_, _, ok := DecodeTTLValue(EncodeTTLValue(nil, time.Time{})[1:])
+	ensure.False(t, ok)
+
+	ensure.Nil(t, db.Delete(wo, []byte("raw")))
+	iter := NewTTLIterator(db.NewIteratorWithBounds(ro, nil, []byte("raw")))
+	var keys []string
+	for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	ensure.Nil(t, iter.Err())
+	ensure.DeepEqual(t, keys, []string{"key1", "key2"})
+	iter.SeekToLast()
+	ensure.True(t, iter.Valid())
+	ensure.DeepEqual(t, iter.Value(), []byte("hour"))
+	iter.Close()
+
+	// the expired value is removed by compactions
+	db.CompactRange(Range{nil, nil})
+	raw, err := db.GetBytes(ro, []byte("key3"))
+	ensure.Nil(t, err)
+	ensure.True(t, raw == nil)
+	raw, err = db.GetBytes(ro, []byte("key2"))
+	ensure.Nil(t, err)
+	ensure.True(t, raw != nil)
+	// so are not the plain values
+	raw, err = db.GetBytes(ro, []byte("raw8"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, raw, []byte("\x00\x00\x00\x00\x00\x00\x00\x01plain"))
+}
@@ -84,3 +84,11 @@ func sizeSlice(data *C.size_t, len C.int) []C.size_t {
 	sH.Cap, sH.Len, sH.Data = int(len), int(len), uintptr(unsafe.Pointer(data))
 	return value
 }
+
+// copyBytes returns a copy of b, keeping nil and empty slices apart.
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
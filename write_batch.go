@@ -22,7 +22,9 @@ func NewWriteBatch() *WriteBatch {
 
 // NewNativeWriteBatch create a WriteBatch object.
 func NewNativeWriteBatch(c *C.rocksdb_writebatch_t) *WriteBatch {
-	return &WriteBatch{c: c}
+	wb := &WriteBatch{c: c}
+	trackAlloc("WriteBatch", wb)
+	return wb
 }
 
 // WriteBatchFrom creates a write batch from a serialized WriteBatch.
@@ -171,6 +173,7 @@ func (wb *WriteBatch) Clear() {
 
 // Destroy deallocates the WriteBatch object.
 func (wb *WriteBatch) Destroy() {
+	trackFree(wb)
 	C.rocksdb_writebatch_destroy(wb.c)
 	wb.c = nil
 	for _, slice := range wb.charsSlices {